@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/JeanLeonHenry/pokedex/api"
+)
+
+func (c *config) printAbility(ctx context.Context, args ...string) {
+	if len(args) != 1 {
+		log.Println("usage: ability <name>")
+		return
+	}
+	abilityName := args[0]
+	var ability api.Ability
+	err := getResource[api.Ability](ctx, c, c.client.AbilityURL(abilityName), &ability, c.client.GetAbility)
+	if errors.Is(err, api.ErrNotFound) {
+		fmt.Println("No such ability:", abilityName)
+		return
+	} else if err != nil {
+		printError(err)
+		return
+	}
+	fmt.Printf("Name: %v\n", ability.Name)
+	if summary := ability.ShortEffect(c.language); summary != "" {
+		fmt.Printf("Summary: %v\n", summary)
+	}
+	if effect := ability.Effect(c.language); effect != "" {
+		fmt.Printf("Effect: %v\n", effect)
+	}
+	fmt.Println("Pokemon:")
+	for _, pokemon := range ability.Pokemon {
+		if pokemon.IsHidden {
+			fmt.Printf("\t- %v (hidden)\n", pokemon.Pokemon.Name)
+		} else {
+			fmt.Printf("\t- %v\n", pokemon.Pokemon.Name)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultBaseURL   string = "https://pokeapi.co/api/v2/"
+	DefaultUserAgent string = "pokedex (github.com/JeanLeonHenry/pokedex)"
+)
+
+// Client polls a PokeAPI instance. The zero value isn't usable, build one with NewClient.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	userAgent  string
+	timeout    time.Duration
+	retry      RetryPolicy
+	limiter    *limiter
+	// observeWait, if set, is told about requests throttled by the limiter.
+	observeWait func(url string, waited time.Duration)
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithBaseURL points the client at another PokeAPI instance, e.g. a self-hosted mirror.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		if !strings.HasSuffix(baseURL, "/") {
+			baseURL += "/"
+		}
+		c.baseURL = baseURL
+	}
+}
+
+// WithHTTPClient sets the http.Client used for every request.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) { c.httpClient = httpClient }
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) { c.userAgent = userAgent }
+}
+
+// WithTimeout bounds every attempt at a request to the given duration, on top of the caller's context. Zero means no bound.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) { c.timeout = timeout }
+}
+
+// NewClient returns a client for the public pokeapi, unless configured otherwise.
+func NewClient(options ...Option) *Client {
+	c := &Client{
+		baseURL:    DefaultBaseURL,
+		httpClient: http.DefaultClient,
+		userAgent:  DefaultUserAgent,
+		retry:      DefaultRetryPolicy,
+		limiter:    newLimiter(DefaultRateLimit),
+	}
+	for _, option := range options {
+		option(c)
+	}
+	return c
+}
+
+// BaseURL returns the root of the api, with a trailing slash.
+func (c *Client) BaseURL() string { return c.baseURL }
+
+// PokemonURL returns the url of the given pokemon resource.
+func (c *Client) PokemonURL(name string) string { return c.baseURL + "pokemon/" + name }
+
+// PokemonSpeciesURL returns the url of the given pokemon species resource.
+func (c *Client) PokemonSpeciesURL(name string) string { return c.baseURL + "pokemon-species/" + name }
+
+// TypeURL returns the url of the given type resource.
+func (c *Client) TypeURL(name string) string { return c.baseURL + "type/" + name }
+
+// MoveURL returns the url of the given move resource.
+func (c *Client) MoveURL(name string) string { return c.baseURL + "move/" + name }
+
+// AbilityURL returns the url of the given ability resource.
+func (c *Client) AbilityURL(name string) string { return c.baseURL + "ability/" + name }
+
+// ItemURL returns the url of the given item resource.
+func (c *Client) ItemURL(name string) string { return c.baseURL + "item/" + name }
+
+// BerryURL returns the url of the given berry resource.
+func (c *Client) BerryURL(name string) string { return c.baseURL + "berry/" + name }
+
+// LocationAreaURL returns the url of the given location area resource.
+func (c *Client) LocationAreaURL(name string) string { return c.baseURL + "location-area/" + name }
+
+// LocationAreaPage returns the url of the page of location areas starting at offset.
+func (c *Client) LocationAreaPage(offset int) string {
+	return c.LocationAreaURL("") + "?offset=" + strconv.Itoa(offset) + "&limit=20"
+}
+
+// LocationAreaFirstPage returns the url of the first page of location areas.
+func (c *Client) LocationAreaFirstPage() string {
+	return c.LocationAreaPage(0)
+}
@@ -0,0 +1,50 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrNotFound is matched by errors.Is when the pokeapi has no resource at the requested url.
+var ErrNotFound = errors.New("resource not found")
+
+// StatusError is returned when the pokeapi answers with a non 2xx status code.
+type StatusError struct {
+	URL        string
+	StatusCode int
+	Body       []byte
+	// RetryAfter is how long the server asked to wait before retrying, if it did.
+	RetryAfter time.Duration
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%v: response failed with status code %d", e.URL, e.StatusCode)
+}
+
+// Is reports a 404 as ErrNotFound.
+func (e *StatusError) Is(target error) bool {
+	return target == ErrNotFound && e.StatusCode == 404
+}
+
+// TransportError is returned when the request couldn't be sent or its body couldn't be read.
+type TransportError struct {
+	URL string
+	Err error
+}
+
+func (e *TransportError) Error() string {
+	return fmt.Sprintf("%v: %v", e.URL, e.Err)
+}
+func (e *TransportError) Unwrap() error { return e.Err }
+
+// DecodeError is returned when a response body isn't the expected json.
+type DecodeError struct {
+	URL string
+	Err error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("%v: couldn't decode response: %v", e.URL, e.Err)
+}
+func (e *DecodeError) Unwrap() error { return e.Err }
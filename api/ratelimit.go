@@ -0,0 +1,79 @@
+package api
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimit throttles the requests of a client with a token bucket: Burst requests can be
+// sent at once, then PerSecond requests per second. A zero PerSecond disables throttling.
+type RateLimit struct {
+	PerSecond float64
+	Burst     int
+}
+
+// DefaultRateLimit is used by clients built without WithRateLimit.
+var DefaultRateLimit = RateLimit{PerSecond: 10, Burst: 10}
+
+// WithRateLimit sets the throttling of the client's requests. Use RateLimit{} to never throttle.
+func WithRateLimit(limit RateLimit) Option {
+	return func(c *Client) { c.limiter = newLimiter(limit) }
+}
+
+// WithWaitObserver registers a func called after every request that had to wait for the rate limiter,
+// with the url and how long it waited.
+func WithWaitObserver(observe func(url string, waited time.Duration)) Option {
+	return func(c *Client) { c.observeWait = observe }
+}
+
+// limiter is a token bucket shared by every request of a client.
+type limiter struct {
+	mu     sync.Mutex
+	limit  RateLimit
+	tokens float64
+	last   time.Time
+}
+
+func newLimiter(limit RateLimit) *limiter {
+	limit.Burst = max(limit.Burst, 1)
+	return &limiter{limit: limit, tokens: float64(limit.Burst), last: time.Now()}
+}
+
+// reserve takes a token, and returns how long to wait before it's actually available.
+func (l *limiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	l.tokens = min(l.tokens+now.Sub(l.last).Seconds()*l.limit.PerSecond, float64(l.limit.Burst))
+	l.last = now
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.limit.PerSecond * float64(time.Second))
+}
+
+// cancel hands back a token that was reserved but not used.
+func (l *limiter) cancel() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tokens = min(l.tokens+1, float64(l.limit.Burst))
+}
+
+// wait blocks until a request may be sent, or ctx is done, and returns how long it waited.
+func (l *limiter) wait(ctx context.Context) (time.Duration, error) {
+	if l == nil || l.limit.PerSecond <= 0 {
+		return 0, nil
+	}
+	delay := l.reserve()
+	if delay == 0 {
+		return 0, nil
+	}
+	start := time.Now()
+	if err := sleep(ctx, delay); err != nil {
+		l.cancel()
+		return time.Since(start), err
+	}
+	return time.Since(start), nil
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRateLimit(t *testing.T) {
+	const perSecond = 50
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(PokemonDetails{Name: "pikachu"})
+	}))
+	defer server.Close()
+
+	var mu sync.Mutex
+	var throttled int
+	client := NewClient(
+		WithBaseURL(server.URL),
+		WithRateLimit(RateLimit{PerSecond: perSecond, Burst: 2}),
+		WithWaitObserver(func(url string, waited time.Duration) {
+			mu.Lock()
+			throttled++
+			mu.Unlock()
+		}),
+	)
+
+	start := time.Now()
+	for range 5 {
+		if _, err := client.GetPokemonDetails(context.Background(), client.PokemonURL("pikachu")); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	// the burst goes through at once, the 3 other requests wait for a token each
+	if elapsed := time.Since(start); elapsed < 3*time.Second/perSecond {
+		t.Errorf("expected requests to be throttled, took %v", elapsed)
+	}
+	if throttled != 3 {
+		t.Errorf("expected 3 throttled requests, got %v", throttled)
+	}
+}
+
+func TestRateLimitCancel(t *testing.T) {
+	l := newLimiter(RateLimit{PerSecond: 1, Burst: 1})
+	if waited, err := l.wait(context.Background()); waited != 0 || err != nil {
+		t.Fatalf("expected first token to be available, waited %v: %v", waited, err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := l.wait(ctx); err == nil {
+		t.Errorf("expected wait to be cancelled")
+	}
+	if l.tokens < 0 {
+		t.Errorf("expected cancelled reservation to be handed back, got %v tokens", l.tokens)
+	}
+}
@@ -1,69 +1,142 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"io"
-	"log"
 	"net/http"
 )
 
-const (
-	PokemonEndpoint       string = "https://pokeapi.co/api/v2/pokemon/"
-	LocationAreaEndpoint  string = "https://pokeapi.co/api/v2/location-area/"
-	LocationAreaFirstPage string = LocationAreaEndpoint + "?offset=0&limit=20"
-)
+func (c *Client) pollApi(ctx context.Context, url string) (body []byte, err error) {
+	for attempt := 0; ; attempt++ {
+		body, err = c.get(ctx, url)
+		if err == nil || attempt+1 >= c.retry.MaxAttempts {
+			return body, err
+		}
+		retry, wait := shouldRetry(ctx, err)
+		if !retry {
+			return body, err
+		}
+		delay := c.retry.backoff(attempt)
+		if wait > 0 {
+			if c.retry.MaxDelay > 0 && wait > c.retry.MaxDelay {
+				return body, err
+			}
+			delay = wait
+		}
+		if sleepErr := sleep(ctx, delay); sleepErr != nil {
+			return body, err
+		}
+	}
+}
 
-func pollApi(url string) []byte {
-	res, err := http.Get(url)
+// get performs a single attempt at fetching url, once the rate limiter allows it.
+func (c *Client) get(ctx context.Context, url string) ([]byte, error) {
+	waited, err := c.limiter.wait(ctx)
+	if waited > 0 && c.observeWait != nil {
+		c.observeWait(url, waited)
+	}
+	if err != nil {
+		return nil, &TransportError{URL: url, Err: err}
+	}
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, &TransportError{URL: url, Err: err}
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+	res, err := c.httpClient.Do(req)
 	if err != nil {
-		log.Fatal(err)
+		return nil, &TransportError{URL: url, Err: err}
 	}
 	body, err := io.ReadAll(res.Body)
 	res.Body.Close()
 	if res.StatusCode > 299 {
-		log.Fatalf("Response failed with status code: %d and\nbody: %s\n", res.StatusCode, body)
+		return nil, &StatusError{
+			URL:        url,
+			StatusCode: res.StatusCode,
+			Body:       body,
+			RetryAfter: parseRetryAfter(res.Header.Get("Retry-After")),
+		}
+	}
+	if err != nil {
+		return nil, &TransportError{URL: url, Err: err}
 	}
+	return body, nil
+}
+
+// decode polls the given url and unpacks the json response into a T.
+func decode[T any](ctx context.Context, c *Client, url string) (result T, err error) {
+	body, err := c.pollApi(ctx, url)
 	if err != nil {
-		log.Fatal(err)
+		return result, err
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return result, &DecodeError{URL: url, Err: err}
 	}
-	return body
+	return result, nil
 }
 
 // GetLocationsPage polls the pokeapi for an api.Limit number of location areas, starting from given page.
-func GetLocationsPage(url string) LocationAreaResponse {
+func (c *Client) GetLocationsPage(ctx context.Context, url string) (LocationAreaResponse, error) {
 	if url == "" {
-		url = LocationAreaFirstPage
-	}
-	body := pollApi(url)
-	var locations LocationAreaResponse
-	err := json.Unmarshal(body, &locations)
-	if err != nil {
-		log.Fatal(err)
+		url = c.LocationAreaFirstPage()
 	}
-	return locations
+	return decode[LocationAreaResponse](ctx, c, url)
 }
 
 // GetPokemonsInArea polls the pokeapi for the given location and returns the local pokemons.
-func GetPokemonsInArea(url string) (result PokemonSlice) {
-	body := pollApi(url)
-	var location LocationArea
-	err := json.Unmarshal(body, &location)
+func (c *Client) GetPokemonsInArea(ctx context.Context, url string) (result PokemonSlice, err error) {
+	location, err := decode[LocationArea](ctx, c, url)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 	for _, encounter := range location.PokemonEncounters {
 		result = append(result, encounter.Pokemon)
 	}
-	return result
+	return result, nil
 }
 
 // GetPokemonDetails polls the pokeapi for details on the given pokemon.
-func GetPokemonDetails(url string) PokemonDetails {
-	body := pollApi(url)
-	var details PokemonDetails
-	err := json.Unmarshal(body, &details)
-	if err != nil {
-		log.Fatal(err)
-	}
-	return details
+func (c *Client) GetPokemonDetails(ctx context.Context, url string) (PokemonDetails, error) {
+	return decode[PokemonDetails](ctx, c, url)
+}
+
+// GetPokemonSpecies polls the pokeapi for the species of a pokemon.
+func (c *Client) GetPokemonSpecies(ctx context.Context, url string) (PokemonSpecies, error) {
+	return decode[PokemonSpecies](ctx, c, url)
+}
+
+// GetEvolutionChain polls the pokeapi for an evolution chain, as linked from a pokemon species.
+func (c *Client) GetEvolutionChain(ctx context.Context, url string) (EvolutionChain, error) {
+	return decode[EvolutionChain](ctx, c, url)
+}
+
+// GetTypeDetails polls the pokeapi for a type, along with its damage relations.
+func (c *Client) GetTypeDetails(ctx context.Context, url string) (TypeDetails, error) {
+	return decode[TypeDetails](ctx, c, url)
+}
+
+// GetMove polls the pokeapi for details on a move.
+func (c *Client) GetMove(ctx context.Context, url string) (Move, error) {
+	return decode[Move](ctx, c, url)
+}
+
+// GetAbility polls the pokeapi for details on an ability, along with the pokemons that can have it.
+func (c *Client) GetAbility(ctx context.Context, url string) (Ability, error) {
+	return decode[Ability](ctx, c, url)
+}
+
+// GetItem polls the pokeapi for details on an item.
+func (c *Client) GetItem(ctx context.Context, url string) (Item, error) {
+	return decode[Item](ctx, c, url)
+}
+
+// GetBerry polls the pokeapi for details on a berry.
+func (c *Client) GetBerry(ctx context.Context, url string) (Berry, error) {
+	return decode[Berry](ctx, c, url)
 }
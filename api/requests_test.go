@@ -0,0 +1,180 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestGetPokemonDetailsErrors(t *testing.T) {
+	cases := []struct {
+		status int
+		body   string
+		check  func(error) bool
+	}{
+		{
+			status: http.StatusNotFound,
+			body:   "Not Found",
+			check:  func(err error) bool { return errors.Is(err, ErrNotFound) },
+		},
+		{
+			status: http.StatusInternalServerError,
+			body:   "oops",
+			check: func(err error) bool {
+				var statusErr *StatusError
+				return errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusInternalServerError
+			},
+		},
+		{
+			status: http.StatusOK,
+			body:   "{not json",
+			check: func(err error) bool {
+				var decodeErr *DecodeError
+				return errors.As(err, &decodeErr)
+			},
+		},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(c.status)
+				fmt.Fprint(w, c.body)
+			}))
+			defer server.Close()
+
+			client := NewClient(WithBaseURL(server.URL), WithRetry(RetryPolicy{}))
+			_, err := client.GetPokemonDetails(context.Background(), client.PokemonURL("missingno"))
+			if !c.check(err) {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestTransportError(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	client := NewClient(WithBaseURL(url), WithRetry(RetryPolicy{}))
+	_, err := client.GetPokemonDetails(context.Background(), client.PokemonURL("pikachu"))
+	var transportErr *TransportError
+	if !errors.As(err, &transportErr) {
+		t.Errorf("expected a transport error, got %v", err)
+	}
+}
+
+func TestClientOptions(t *testing.T) {
+	const userAgent = "pokedex-test"
+	var gotPath, gotUserAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotUserAgent = r.URL.Path, r.UserAgent()
+		json.NewEncoder(w).Encode(PokemonDetails{Name: "pikachu"})
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL+"/api/v2"), WithHTTPClient(server.Client()), WithUserAgent(userAgent))
+	details, err := client.GetPokemonDetails(context.Background(), client.PokemonURL("pikachu"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if details.Name != "pikachu" {
+		t.Errorf("expected pikachu, got %v", details.Name)
+	}
+	if gotPath != "/api/v2/pokemon/pikachu" {
+		t.Errorf("unexpected path %v", gotPath)
+	}
+	if gotUserAgent != userAgent {
+		t.Errorf("unexpected user agent %v", gotUserAgent)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithTimeout(10*time.Millisecond))
+	_, err := client.GetPokemonDetails(context.Background(), client.PokemonURL("slowpoke"))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline to be exceeded, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = NewClient(WithBaseURL(server.URL)).GetPokemonDetails(ctx, client.PokemonURL("slowpoke"))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected request to be cancelled, got %v", err)
+	}
+}
+
+func TestRetry(t *testing.T) {
+	cases := []struct {
+		responses []int
+		// retryAfter is sent along with 429 responses
+		retryAfter string
+		policy     RetryPolicy
+		wantErr    bool
+		wantCalls  int
+	}{
+		{
+			// a Retry-After date in the past means retrying right away, without making the test wait
+			responses:  []int{http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusOK},
+			retryAfter: time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat),
+			policy:     RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Second},
+			wantCalls:  3,
+		},
+		{
+			responses: []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusOK},
+			policy:    RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Second},
+			wantErr:   true,
+			wantCalls: 2,
+		},
+		{
+			responses: []int{http.StatusNotFound, http.StatusOK},
+			policy:    RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Second},
+			wantErr:   true,
+			wantCalls: 1,
+		},
+		{
+			// Retry-After asks for longer than MaxDelay
+			responses:  []int{http.StatusTooManyRequests, http.StatusOK},
+			retryAfter: "1",
+			policy:     RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 500 * time.Millisecond},
+			wantErr:    true,
+			wantCalls:  1,
+		},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
+			calls := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				status := c.responses[calls]
+				calls++
+				if status == http.StatusTooManyRequests {
+					w.Header().Set("Retry-After", c.retryAfter)
+				}
+				w.WriteHeader(status)
+				json.NewEncoder(w).Encode(PokemonDetails{Name: "pikachu"})
+			}))
+			defer server.Close()
+
+			client := NewClient(WithBaseURL(server.URL), WithRetry(c.policy))
+			_, err := client.GetPokemonDetails(context.Background(), client.PokemonURL("pikachu"))
+			if (err != nil) != c.wantErr {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if calls != c.wantCalls {
+				t.Errorf("expected %v calls, got %v", c.wantCalls, calls)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy decides how many times, and how long apart, a failed GET is attempted.
+// Transport errors, 429 and 5xx responses are retried, other failures aren't.
+type RetryPolicy struct {
+	// MaxAttempts caps the number of attempts, including the first one. Below 2, nothing is retried.
+	MaxAttempts int
+	// BaseDelay is the backoff before the first retry, doubled on each following one.
+	BaseDelay time.Duration
+	// MaxDelay caps the backoff. A server asking, through Retry-After, to wait longer than this is given up on.
+	MaxDelay time.Duration
+}
+
+// DefaultRetryPolicy is used by clients built without WithRetry.
+var DefaultRetryPolicy = RetryPolicy{MaxAttempts: 3, BaseDelay: 250 * time.Millisecond, MaxDelay: 5 * time.Second}
+
+// WithRetry sets the retry policy of the client. Use RetryPolicy{} to never retry.
+func WithRetry(policy RetryPolicy) Option {
+	return func(c *Client) { c.retry = policy }
+}
+
+// backoff returns a jittered delay before the given retry, counting from 0.
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	ceiling := p.BaseDelay << retry
+	if ceiling <= 0 || (p.MaxDelay > 0 && ceiling > p.MaxDelay) {
+		ceiling = p.MaxDelay
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return ceiling/2 + rand.N(ceiling/2+1)
+}
+
+// shouldRetry tells whether err is worth another attempt, and how long to wait if the server said so.
+func shouldRetry(ctx context.Context, err error) (retry bool, wait time.Duration) {
+	if ctx.Err() != nil {
+		return false, 0
+	}
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		retry = statusErr.StatusCode == http.StatusTooManyRequests || statusErr.StatusCode >= 500
+		return retry, statusErr.RetryAfter
+	}
+	var transportErr *TransportError
+	return errors.As(err, &transportErr), 0
+}
+
+// parseRetryAfter reads a Retry-After header, given either in seconds or as an http date.
+func parseRetryAfter(header string) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(header); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(header); err == nil {
+		return max(time.Until(date), 0)
+	}
+	return 0
+}
+
+// sleep waits for d, or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
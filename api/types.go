@@ -1,6 +1,11 @@
 package api
 
-import "fmt"
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
 
 type LocationAreaResponse struct {
 	Count    int           `json:"count"`
@@ -332,21 +337,18 @@ type PokemonDetails struct {
 		Latest string `json:"latest"`
 		Legacy string `json:"legacy"`
 	} `json:"cries"`
-	Stats     StatSlice `json:"stats"`
-	Types     TypeSlice `json:"types"`
-	PastTypes []struct {
-		Generation struct {
-			Name string `json:"name"`
-			URL  string `json:"url"`
-		} `json:"generation"`
-		Types []struct {
-			Slot int `json:"slot"`
-			Type struct {
-				Name string `json:"name"`
-				URL  string `json:"url"`
-			} `json:"type"`
-		} `json:"types"`
-	} `json:"past_types"`
+	Stats     StatSlice  `json:"stats"`
+	Types     TypeSlice  `json:"types"`
+	PastTypes []PastType `json:"past_types"`
+}
+
+// PastType holds the types a pokemon had up to, and including, a generation.
+type PastType struct {
+	Generation struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"generation"`
+	Types TypeSlice `json:"types"`
 }
 
 type Type struct {
@@ -388,5 +390,535 @@ func (p PokemonDetails) String() (result string) {
 	result += fmt.Sprintf("Weight: %v\n", p.Weight)
 	result += fmt.Sprintf("Stats: %v\n", p.Stats)
 	result += fmt.Sprintf("Types: %v\n", p.Types)
+	if len(p.HeldItems) > 0 {
+		result += "Held items:\n"
+	}
+	for _, held := range p.HeldItems {
+		// versions sharing a rarity are listed together
+		var rarities []int
+		versions := make(map[int][]string)
+		for _, detail := range held.VersionDetails {
+			if _, ok := versions[detail.Rarity]; !ok {
+				rarities = append(rarities, detail.Rarity)
+			}
+			versions[detail.Rarity] = append(versions[detail.Rarity], detail.Version.Name)
+		}
+		var chances []string
+		for _, rarity := range rarities {
+			chances = append(chances, fmt.Sprintf("%v%% in %v", rarity, strings.Join(versions[rarity], ", ")))
+		}
+		result += fmt.Sprintf("\t- %v (%v)\n", held.Item.Name, strings.Join(chances, "; "))
+	}
+	result += "Abilities:\n"
+	for _, ability := range p.Abilities {
+		result += fmt.Sprintf("\t- %v", ability.Ability.Name)
+		if ability.IsHidden {
+			result += " (hidden)"
+		}
+		result += "\n"
+	}
+	return
+}
+
+type Genus struct {
+	Genus    string   `json:"genus"`
+	Language Language `json:"language"`
+}
+type FlavorText struct {
+	FlavorText string   `json:"flavor_text"`
+	Language   Language `json:"language"`
+	Version    Version  `json:"version"`
+}
+type EggGroup struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+type GrowthRate struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+type EvolutionChainLink struct {
+	URL string `json:"url"`
+}
+type PokemonSpeciesRef struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+type PokemonSpecies struct {
+	ID                 int                `json:"id"`
+	Name               string             `json:"name"`
+	Order              int                `json:"order"`
+	GenderRate         int                `json:"gender_rate"`
+	CaptureRate        int                `json:"capture_rate"`
+	BaseHappiness      int                `json:"base_happiness"`
+	IsBaby             bool               `json:"is_baby"`
+	IsLegendary        bool               `json:"is_legendary"`
+	IsMythical         bool               `json:"is_mythical"`
+	HatchCounter       int                `json:"hatch_counter"`
+	GrowthRate         GrowthRate         `json:"growth_rate"`
+	EggGroups          []EggGroup         `json:"egg_groups"`
+	EvolvesFromSpecies *PokemonSpeciesRef `json:"evolves_from_species"`
+	EvolutionChain     EvolutionChainLink `json:"evolution_chain"`
+	Names              []Name             `json:"names"`
+	Genera             []Genus            `json:"genera"`
+	FlavorTextEntries  []FlavorText       `json:"flavor_text_entries"`
+	Varieties          []struct {
+		IsDefault bool    `json:"is_default"`
+		Pokemon   Pokemon `json:"pokemon"`
+	} `json:"varieties"`
+}
+
+// DefaultVariety returns the pokemon that is the default form of the species.
+func (s PokemonSpecies) DefaultVariety() (Pokemon, bool) {
+	for _, variety := range s.Varieties {
+		if variety.IsDefault {
+			return variety.Pokemon, true
+		}
+	}
+	return Pokemon{}, false
+}
+
+// DefaultLanguage is used when a text isn't available in the requested language.
+const DefaultLanguage = "en"
+
+// Genus returns the genus of the species in the given language, e.g. "Mouse Pokémon".
+func (s PokemonSpecies) Genus(language string) string {
+	return localized(s.Genera, language, func(g Genus) (string, string) { return g.Language.Name, g.Genus })
+}
+
+// FlavorText returns the latest pokedex entry of the species in the given language, on a single line.
+func (s PokemonSpecies) FlavorText(language string) string {
+	entries := slices.Clone(s.FlavorTextEntries)
+	slices.Reverse(entries)
+	text := localized(entries, language, func(f FlavorText) (string, string) { return f.Language.Name, f.FlavorText })
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// localized returns the first text of entries in language, falling back to DefaultLanguage.
+func localized[T any](entries []T, language string, text func(T) (lang string, text string)) string {
+	fallback := ""
+	for _, entry := range entries {
+		lang, t := text(entry)
+		if lang == language {
+			return t
+		}
+		if lang == DefaultLanguage && fallback == "" {
+			fallback = t
+		}
+	}
+	return fallback
+}
+
+type NamedResource struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+type EvolutionChain struct {
+	ID              int            `json:"id"`
+	BabyTriggerItem *NamedResource `json:"baby_trigger_item"`
+	Chain           ChainLink      `json:"chain"`
+}
+
+// ChainLink is a species of an evolution chain, along with the species it evolves to.
+type ChainLink struct {
+	IsBaby           bool              `json:"is_baby"`
+	Species          PokemonSpeciesRef `json:"species"`
+	EvolutionDetails []EvolutionDetail `json:"evolution_details"`
+	EvolvesTo        []ChainLink       `json:"evolves_to"`
+}
+
+// EvolutionDetail is one way of evolving into a species: a trigger, and the conditions that must hold.
+type EvolutionDetail struct {
+	Trigger               NamedResource  `json:"trigger"`
+	Item                  *NamedResource `json:"item"`
+	Gender                *int           `json:"gender"`
+	HeldItem              *NamedResource `json:"held_item"`
+	KnownMove             *NamedResource `json:"known_move"`
+	KnownMoveType         *NamedResource `json:"known_move_type"`
+	Location              *NamedResource `json:"location"`
+	MinLevel              *int           `json:"min_level"`
+	MinHappiness          *int           `json:"min_happiness"`
+	MinBeauty             *int           `json:"min_beauty"`
+	MinAffection          *int           `json:"min_affection"`
+	NeedsOverworldRain    bool           `json:"needs_overworld_rain"`
+	PartySpecies          *NamedResource `json:"party_species"`
+	PartyType             *NamedResource `json:"party_type"`
+	RelativePhysicalStats *int           `json:"relative_physical_stats"`
+	TimeOfDay             string         `json:"time_of_day"`
+	TradeSpecies          *NamedResource `json:"trade_species"`
+	TurnUpsideDown        bool           `json:"turn_upside_down"`
+}
+
+func (d EvolutionDetail) String() string {
+	var conditions []string
+	switch d.Trigger.Name {
+	case "level-up":
+		if d.MinLevel != nil {
+			conditions = append(conditions, fmt.Sprint("level ", *d.MinLevel))
+		} else {
+			conditions = append(conditions, "level up")
+		}
+	case "use-item":
+		if d.Item != nil {
+			conditions = append(conditions, "use "+d.Item.Name)
+		}
+	case "trade":
+		conditions = append(conditions, "trade")
+	default:
+		conditions = append(conditions, d.Trigger.Name)
+	}
+	if d.HeldItem != nil {
+		conditions = append(conditions, "holding "+d.HeldItem.Name)
+	}
+	if d.TradeSpecies != nil {
+		conditions = append(conditions, "for "+d.TradeSpecies.Name)
+	}
+	if d.MinHappiness != nil {
+		conditions = append(conditions, fmt.Sprint("happiness ", *d.MinHappiness))
+	}
+	if d.MinAffection != nil {
+		conditions = append(conditions, fmt.Sprint("affection ", *d.MinAffection))
+	}
+	if d.MinBeauty != nil {
+		conditions = append(conditions, fmt.Sprint("beauty ", *d.MinBeauty))
+	}
+	if d.TimeOfDay != "" {
+		conditions = append(conditions, "during the "+d.TimeOfDay)
+	}
+	if d.KnownMove != nil {
+		conditions = append(conditions, "knowing "+d.KnownMove.Name)
+	}
+	if d.KnownMoveType != nil {
+		conditions = append(conditions, "knowing a "+d.KnownMoveType.Name+" move")
+	}
+	if d.Location != nil {
+		conditions = append(conditions, "at "+d.Location.Name)
+	}
+	if d.Gender != nil {
+		conditions = append(conditions, map[int]string{1: "female", 2: "male"}[*d.Gender])
+	}
+	if d.PartySpecies != nil {
+		conditions = append(conditions, "with "+d.PartySpecies.Name+" in party")
+	}
+	if d.PartyType != nil {
+		conditions = append(conditions, "with a "+d.PartyType.Name+" pokemon in party")
+	}
+	if d.RelativePhysicalStats != nil {
+		conditions = append(conditions, map[int]string{1: "attack > defense", 0: "attack = defense", -1: "attack < defense"}[*d.RelativePhysicalStats])
+	}
+	if d.NeedsOverworldRain {
+		conditions = append(conditions, "while raining")
+	}
+	if d.TurnUpsideDown {
+		conditions = append(conditions, "upside down")
+	}
+	return strings.Join(conditions, ", ")
+}
+
+// Find returns the link of the given species in the chain, if it's there.
+func (l *ChainLink) Find(species string) (*ChainLink, bool) {
+	if l.Species.Name == species {
+		return l, true
+	}
+	for i := range l.EvolvesTo {
+		if found, ok := l.EvolvesTo[i].Find(species); ok {
+			return found, true
+		}
+	}
+	return nil, false
+}
+
+// String renders the chain as a tree, with the conditions of each evolution.
+func (l ChainLink) String() string {
+	var result strings.Builder
+	result.WriteString(l.Species.Name + "\n")
+	l.writeEvolutions(&result, "")
+	return result.String()
+}
+
+func (l ChainLink) writeEvolutions(result *strings.Builder, indent string) {
+	for i, next := range l.EvolvesTo {
+		branch, childIndent := "├── ", "│   "
+		if i == len(l.EvolvesTo)-1 {
+			branch, childIndent = "└── ", "    "
+		}
+		conditions := make([]string, 0, len(next.EvolutionDetails))
+		for _, detail := range next.EvolutionDetails {
+			conditions = append(conditions, detail.String())
+		}
+		result.WriteString(indent + branch + next.Species.Name)
+		if len(conditions) > 0 {
+			result.WriteString(" (" + strings.Join(conditions, " or ") + ")")
+		}
+		result.WriteString("\n")
+		next.writeEvolutions(result, indent+childIndent)
+	}
+}
+
+type DamageRelations struct {
+	NoDamageTo       []NamedResource `json:"no_damage_to"`
+	HalfDamageTo     []NamedResource `json:"half_damage_to"`
+	DoubleDamageTo   []NamedResource `json:"double_damage_to"`
+	NoDamageFrom     []NamedResource `json:"no_damage_from"`
+	HalfDamageFrom   []NamedResource `json:"half_damage_from"`
+	DoubleDamageFrom []NamedResource `json:"double_damage_from"`
+}
+type PastDamageRelations struct {
+	Generation      NamedResource   `json:"generation"`
+	DamageRelations DamageRelations `json:"damage_relations"`
+}
+
+// TypeDetails is a type resource, e.g. fire, as opposed to the Type slot of a pokemon.
+type TypeDetails struct {
+	ID                  int                   `json:"id"`
+	Name                string                `json:"name"`
+	Generation          NamedResource         `json:"generation"`
+	DamageRelations     DamageRelations       `json:"damage_relations"`
+	PastDamageRelations []PastDamageRelations `json:"past_damage_relations"`
+}
+
+// DamageRelationsIn returns the damage relations of the type in the given generation, or the current
+// ones if generation is empty.
+func (t TypeDetails) DamageRelationsIn(generation string) DamageRelations {
+	if generation == "" {
+		return t.DamageRelations
+	}
+	// past relations hold up to, and including, their generation
+	for _, past := range sortedByGeneration(t.PastDamageRelations, func(p PastDamageRelations) string { return p.Generation.Name }) {
+		if GenerationNumber(generation) <= GenerationNumber(past.Generation.Name) {
+			return past.DamageRelations
+		}
+	}
+	return t.DamageRelations
+}
+
+// TypesIn returns the names of the types of the pokemon in the given generation, or the current ones
+// if generation is empty.
+func (p PokemonDetails) TypesIn(generation string) (names []string) {
+	types := p.Types
+	if generation != "" {
+		// past types hold up to, and including, their generation
+		for _, past := range sortedByGeneration(p.PastTypes, func(past PastType) string { return past.Generation.Name }) {
+			if GenerationNumber(generation) <= GenerationNumber(past.Generation.Name) {
+				types = past.Types
+				break
+			}
+		}
+	}
+	for _, t := range types {
+		names = append(names, t.Type.Name)
+	}
+	return names
+}
+
+// GenerationNumber returns the number of a generation from its name, e.g. 4 for generation-iv, or 0.
+func GenerationNumber(generation string) int {
+	numeral, ok := strings.CutPrefix(generation, "generation-")
+	if !ok || numeral == "" {
+		return 0
+	}
+	values := map[rune]int{'i': 1, 'v': 5, 'x': 10}
+	number := 0
+	for i, r := range numeral {
+		value, ok := values[r]
+		if !ok {
+			return 0
+		}
+		if i+1 < len(numeral) && value < values[rune(numeral[i+1])] {
+			number -= value
+		} else {
+			number += value
+		}
+	}
+	return number
+}
+
+func sortedByGeneration[T any](entries []T, generation func(T) string) []T {
+	sorted := slices.Clone(entries)
+	slices.SortFunc(sorted, func(a, b T) int {
+		return GenerationNumber(generation(a)) - GenerationNumber(generation(b))
+	})
+	return sorted
+}
+
+// Move is a move resource, as opposed to the moves a pokemon can learn.
+type Move struct {
+	ID          int           `json:"id"`
+	Name        string        `json:"name"`
+	Accuracy    *int          `json:"accuracy"`
+	Power       *int          `json:"power"`
+	PP          int           `json:"pp"`
+	Priority    int           `json:"priority"`
+	Type        NamedResource `json:"type"`
+	DamageClass NamedResource `json:"damage_class"`
+}
+
+// LearnableMove is a move a pokemon can learn in a version group.
+type LearnableMove struct {
+	Name         string
+	URL          string
+	Level        int
+	Method       string
+	VersionGroup string
+}
+
+// LearnableMoves lists the moves the pokemon can learn, in the given version group and by the given
+// learn method, e.g. level-up. Empty filters match anything. A move learnable several ways is listed
+// once per way, sorted by level then name.
+func (p PokemonDetails) LearnableMoves(versionGroup, method string) (moves []LearnableMove) {
+	for _, move := range p.Moves {
+		for _, detail := range move.VersionGroupDetails {
+			if versionGroup != "" && detail.VersionGroup.Name != versionGroup {
+				continue
+			}
+			if method != "" && detail.MoveLearnMethod.Name != method {
+				continue
+			}
+			moves = append(moves, LearnableMove{
+				Name:         move.Move.Name,
+				URL:          move.Move.URL,
+				Level:        detail.LevelLearnedAt,
+				Method:       detail.MoveLearnMethod.Name,
+				VersionGroup: detail.VersionGroup.Name,
+			})
+		}
+	}
+	slices.SortFunc(moves, func(a, b LearnableMove) int {
+		if a.Level != b.Level {
+			return a.Level - b.Level
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return moves
+}
+
+// BaseStat returns the base value of the given stat, e.g. attack, or 0.
+func (p PokemonDetails) BaseStat(name string) int {
+	for _, stat := range p.Stats {
+		if stat.Stat.Name == name {
+			return stat.BaseStat
+		}
+	}
+	return 0
+}
+
+// LatestVersionGroup returns the most recent version group the pokemon learns moves in.
+func (p PokemonDetails) LatestVersionGroup() (latest string) {
+	latestID := 0
+	for _, move := range p.Moves {
+		for _, detail := range move.VersionGroupDetails {
+			if id := ResourceID(detail.VersionGroup.URL); id > latestID {
+				latest, latestID = detail.VersionGroup.Name, id
+			}
+		}
+	}
+	return latest
+}
+
+// ResourceID returns the id at the end of a resource url, e.g. 25 for .../version-group/25/, or 0.
+func ResourceID(url string) int {
+	parts := strings.Split(strings.TrimSuffix(url, "/"), "/")
+	id, err := strconv.Atoi(parts[len(parts)-1])
+	if err != nil {
+		return 0
+	}
+	return id
+}
+
+func (m Move) String() string {
+	return fmt.Sprintf("%v\t%v\t%v\t%v\t%v\t%v", m.Name, m.Type.Name, m.DamageClass.Name, optional(m.Power), optional(m.Accuracy), m.PP)
+}
+
+// optional formats a value PokeAPI may leave out, such as the power of a status move.
+func optional(value *int) string {
+	if value == nil {
+		return "-"
+	}
+	return strconv.Itoa(*value)
+}
+
+type VerboseEffect struct {
+	Effect      string   `json:"effect"`
+	ShortEffect string   `json:"short_effect"`
+	Language    Language `json:"language"`
+}
+type AbilityPokemon struct {
+	IsHidden bool    `json:"is_hidden"`
+	Slot     int     `json:"slot"`
+	Pokemon  Pokemon `json:"pokemon"`
+}
+type Ability struct {
+	ID            int              `json:"id"`
+	Name          string           `json:"name"`
+	IsMainSeries  bool             `json:"is_main_series"`
+	Generation    NamedResource    `json:"generation"`
+	Names         []Name           `json:"names"`
+	EffectEntries []VerboseEffect  `json:"effect_entries"`
+	Pokemon       []AbilityPokemon `json:"pokemon"`
+}
+
+// Effect returns the effect of the ability in the given language, on a single line.
+func (a Ability) Effect(language string) string {
+	effect := localized(a.EffectEntries, language, func(e VerboseEffect) (string, string) { return e.Language.Name, e.Effect })
+	return strings.Join(strings.Fields(effect), " ")
+}
+
+// ShortEffect returns the summary of the effect of the ability in the given language.
+func (a Ability) ShortEffect(language string) string {
+	return localized(a.EffectEntries, language, func(e VerboseEffect) (string, string) { return e.Language.Name, e.ShortEffect })
+}
+
+type Item struct {
+	ID            int             `json:"id"`
+	Name          string          `json:"name"`
+	Cost          int             `json:"cost"`
+	FlingPower    *int            `json:"fling_power"`
+	FlingEffect   *NamedResource  `json:"fling_effect"`
+	Attributes    []NamedResource `json:"attributes"`
+	Category      NamedResource   `json:"category"`
+	EffectEntries []VerboseEffect `json:"effect_entries"`
+	Names         []Name          `json:"names"`
+}
+
+// Effect returns the effect of the item in the given language, on a single line.
+func (i Item) Effect(language string) string {
+	effect := localized(i.EffectEntries, language, func(e VerboseEffect) (string, string) { return e.Language.Name, e.ShortEffect })
+	return strings.Join(strings.Fields(effect), " ")
+}
+
+// BerryName returns the name of the berry the item is, if it's one.
+func (i Item) BerryName() (string, bool) {
+	return strings.CutSuffix(i.Name, "-berry")
+}
+
+type BerryFlavor struct {
+	Potency int           `json:"potency"`
+	Flavor  NamedResource `json:"flavor"`
+}
+type Berry struct {
+	ID               int           `json:"id"`
+	Name             string        `json:"name"`
+	GrowthTime       int           `json:"growth_time"`
+	MaxHarvest       int           `json:"max_harvest"`
+	NaturalGiftPower int           `json:"natural_gift_power"`
+	Size             int           `json:"size"`
+	Smoothness       int           `json:"smoothness"`
+	SoilDryness      int           `json:"soil_dryness"`
+	Firmness         NamedResource `json:"firmness"`
+	Flavors          []BerryFlavor `json:"flavors"`
+	Item             NamedResource `json:"item"`
+	NaturalGiftType  NamedResource `json:"natural_gift_type"`
+}
+
+func (b Berry) String() (result string) {
+	result += fmt.Sprintf("Firmness: %v\n", b.Firmness.Name)
+	result += fmt.Sprintf("Growth time: %vh per stage\n", b.GrowthTime)
+	result += fmt.Sprintf("Natural gift: %v %v\n", b.NaturalGiftType.Name, b.NaturalGiftPower)
+	result += "Flavors:\n"
+	for _, flavor := range b.Flavors {
+		if flavor.Potency > 0 {
+			result += fmt.Sprintf("\t- %v: %v\n", flavor.Flavor.Name, flavor.Potency)
+		}
+	}
 	return
 }
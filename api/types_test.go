@@ -0,0 +1,155 @@
+package api
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSpeciesTexts(t *testing.T) {
+	species := PokemonSpecies{
+		Genera: []Genus{
+			{Genus: "Mouse Pokémon", Language: Language{Name: "en"}},
+			{Genus: "Pokémon Souris", Language: Language{Name: "fr"}},
+		},
+		FlavorTextEntries: []FlavorText{
+			{FlavorText: "Old\nentry.", Language: Language{Name: "en"}},
+			{FlavorText: "When several of\nthese POKéMON\fgather...", Language: Language{Name: "en"}},
+		},
+	}
+	cases := []struct {
+		language   string
+		genus      string
+		flavorText string
+	}{
+		{language: "fr", genus: "Pokémon Souris", flavorText: "When several of these POKéMON gather..."},
+		{language: "ja", genus: "Mouse Pokémon", flavorText: "When several of these POKéMON gather..."},
+	}
+	for _, c := range cases {
+		t.Run(c.language, func(t *testing.T) {
+			if genus := species.Genus(c.language); genus != c.genus {
+				t.Errorf("expected genus %q, got %q", c.genus, genus)
+			}
+			if flavorText := species.FlavorText(c.language); flavorText != c.flavorText {
+				t.Errorf("expected flavor text %q, got %q", c.flavorText, flavorText)
+			}
+		})
+	}
+}
+
+func TestChainLinkString(t *testing.T) {
+	level := func(n int) *int { return &n }
+	evolution := func(name string, details ...EvolutionDetail) ChainLink {
+		return ChainLink{Species: PokemonSpeciesRef{Name: name}, EvolutionDetails: details}
+	}
+	eevee := evolution("eevee")
+	eevee.EvolvesTo = []ChainLink{
+		evolution("vaporeon", EvolutionDetail{Trigger: NamedResource{Name: "use-item"}, Item: &NamedResource{Name: "water-stone"}}),
+		evolution("espeon", EvolutionDetail{Trigger: NamedResource{Name: "level-up"}, MinHappiness: level(160), TimeOfDay: "day"}),
+	}
+	pichu := evolution("pichu")
+	pichu.EvolvesTo = []ChainLink{evolution("pikachu", EvolutionDetail{Trigger: NamedResource{Name: "level-up"}, MinHappiness: level(220)})}
+	pichu.EvolvesTo[0].EvolvesTo = []ChainLink{evolution("raichu", EvolutionDetail{Trigger: NamedResource{Name: "use-item"}, Item: &NamedResource{Name: "thunder-stone"}})}
+
+	cases := []struct {
+		chain ChainLink
+		want  string
+	}{
+		{
+			chain: eevee,
+			want: `eevee
+├── vaporeon (use water-stone)
+└── espeon (level up, happiness 160, during the day)
+`,
+		},
+		{
+			chain: pichu,
+			want: `pichu
+└── pikachu (level up, happiness 220)
+    └── raichu (use thunder-stone)
+`,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.chain.Species.Name, func(t *testing.T) {
+			if got := c.chain.String(); got != c.want {
+				t.Errorf("expected\n%v\ngot\n%v", c.want, got)
+			}
+		})
+	}
+	if found, ok := pichu.Find("raichu"); !ok || found.Species.Name != "raichu" {
+		t.Errorf("expected to find raichu in the chain")
+	}
+}
+
+func TestGenerationNumber(t *testing.T) {
+	cases := map[string]int{
+		"generation-i":    1,
+		"generation-iv":   4,
+		"generation-vi":   6,
+		"generation-ix":   9,
+		"generation-":     0,
+		"red-blue":        0,
+		"generation-viii": 8,
+	}
+	for name, want := range cases {
+		if got := GenerationNumber(name); got != want {
+			t.Errorf("expected %v to be generation %v, got %v", name, want, got)
+		}
+	}
+}
+
+func TestLearnableMoves(t *testing.T) {
+	var details PokemonDetails
+	err := json.Unmarshal([]byte(`{"moves": [
+		{"move": {"name": "thunderbolt"}, "version_group_details": [
+			{"level_learned_at": 26, "version_group": {"name": "red-blue", "url": "https://pokeapi.co/api/v2/version-group/1/"}, "move_learn_method": {"name": "level-up"}},
+			{"level_learned_at": 0, "version_group": {"name": "scarlet-violet", "url": "https://pokeapi.co/api/v2/version-group/25/"}, "move_learn_method": {"name": "machine"}}
+		]},
+		{"move": {"name": "thunder-shock"}, "version_group_details": [
+			{"level_learned_at": 1, "version_group": {"name": "red-blue", "url": "https://pokeapi.co/api/v2/version-group/1/"}, "move_learn_method": {"name": "level-up"}}
+		]}
+	]}`), &details)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest := details.LatestVersionGroup(); latest != "scarlet-violet" {
+		t.Errorf("expected scarlet-violet, got %v", latest)
+	}
+	moves := details.LearnableMoves("red-blue", "level-up")
+	if len(moves) != 2 || moves[0].Name != "thunder-shock" || moves[1].Name != "thunderbolt" {
+		t.Errorf("expected thunder-shock then thunderbolt, got %+v", moves)
+	}
+	if moves := details.LearnableMoves("", "machine"); len(moves) != 1 || moves[0].VersionGroup != "scarlet-violet" {
+		t.Errorf("expected a single machine move, got %+v", moves)
+	}
+}
+
+func TestPokemonDetailsString(t *testing.T) {
+	var details PokemonDetails
+	err := json.Unmarshal([]byte(`{
+		"name": "pikachu", "height": 4, "weight": 60,
+		"abilities": [{"is_hidden": false, "ability": {"name": "static"}}, {"is_hidden": true, "ability": {"name": "lightning-rod"}}],
+		"held_items": [{"item": {"name": "light-ball"}, "version_details": [
+			{"rarity": 5, "version": {"name": "ruby"}},
+			{"rarity": 5, "version": {"name": "sapphire"}},
+			{"rarity": 100, "version": {"name": "yellow"}}
+		]}]
+	}`), &details)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `Name: pikachu
+Height: 4
+Weight: 60
+Stats: 
+Types: 
+Held items:
+	- light-ball (5% in ruby, sapphire; 100% in yellow)
+Abilities:
+	- static
+	- lightning-rod (hidden)
+`
+	if got := details.String(); got != want {
+		t.Errorf("expected\n%v\ngot\n%v", want, got)
+	}
+}
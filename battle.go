@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/JeanLeonHenry/pokedex/api"
+	"github.com/JeanLeonHenry/pokedex/battle"
+)
+
+// maxMoves is how many moves a pokemon brings to battle, as in the games.
+const maxMoves = 4
+
+// battlePokemon returns the given pokemon ready for battle, with the last damaging moves it learned by leveling up
+// in the latest version group, as listed by the moves command.
+func (c *config) battlePokemon(ctx context.Context, details api.PokemonDetails, level int) (battle.Pokemon, error) {
+	learnable := details.LearnableMoves(details.LatestVersionGroup(), "level-up")
+	var moves []battle.Move
+	seen := make(map[string]bool)
+	for i := len(learnable) - 1; i >= 0 && len(moves) < maxMoves; i-- {
+		learned := learnable[i]
+		if learned.Level > level || seen[learned.Name] {
+			continue
+		}
+		seen[learned.Name] = true
+		var move api.Move
+		if err := getResource[api.Move](ctx, c, learned.URL, &move, c.client.GetMove); err != nil {
+			return battle.Pokemon{}, err
+		}
+		if battleMove, ok := battle.NewMove(move); ok {
+			moves = append(moves, battleMove)
+		}
+	}
+	return battle.NewPokemon(details, level, moves), nil
+}
+
+func (c *config) startBattle(ctx context.Context, args ...string) {
+	if len(args) != 2 {
+		log.Println("usage: battle <mine> <opponent>")
+		return
+	}
+	mine, ok, err := c.getCaught(ctx, args[0])
+	if !ok {
+		fmt.Println("No", args[0], "in pokedex.")
+		return
+	} else if err != nil {
+		printError(err)
+		return
+	}
+	if args[0] == args[1] {
+		fmt.Println(args[0], "can't battle itself.")
+		return
+	}
+	// the opponent is one of ours if we have it, a wild one otherwise
+	opponent, caught, err := c.getCaught(ctx, args[1])
+	if err != nil {
+		printError(err)
+		return
+	}
+	opponentDetails, opponentLevel := api.PokemonDetails{}, 0
+	if caught {
+		opponentDetails, opponentLevel = opponent.PokemonDetails, opponent.Level()
+	} else {
+		details, err := c.getPokemon(ctx, args[1])
+		if errors.Is(err, api.ErrNotFound) {
+			fmt.Println("No such pokemon:", args[1])
+			return
+		} else if err != nil {
+			printError(err)
+			return
+		}
+		opponentDetails, opponentLevel = details, wildLevel(details)
+	}
+
+	first, err := c.battlePokemon(ctx, mine.PokemonDetails, mine.Level())
+	if err != nil {
+		printError(err)
+		return
+	}
+	second, err := c.battlePokemon(ctx, opponentDetails, opponentLevel)
+	if err != nil {
+		printError(err)
+		return
+	}
+	chart, err := c.getTypeChart(ctx, "", append(first.Types, second.Types...)...)
+	if err != nil {
+		printError(err)
+		return
+	}
+
+	fmt.Printf("lvl %v %v vs lvl %v %v!\n", first.Level, first.Name, second.Level, second.Name)
+	result := battle.Simulate(first, second, chart)
+	for _, line := range result.Log {
+		fmt.Println(line)
+	}
+	// the winner gains experience, if it's one of ours
+	var winner *CaughtPokemon
+	var loserDetails api.PokemonDetails
+	var loserLevel int
+	switch {
+	case result.Winner == 0:
+		winner, loserDetails, loserLevel = mine, opponentDetails, opponentLevel
+	case result.Winner == 1 && caught:
+		winner, loserDetails, loserLevel = opponent, mine.PokemonDetails, mine.Level()
+	default:
+		return
+	}
+	experience := battle.ExperienceYield(loserDetails.BaseExperience, loserLevel)
+	fmt.Println(winner.Name, "gained", experience, "xp!")
+	if before, after := winner.GainExperience(experience); after > before {
+		fmt.Println(winner.Name, "grew to lvl", after, "!")
+	}
+}
@@ -0,0 +1,157 @@
+// Package battle simulates turn-based battles between two pokemon.
+//
+// Battles are deterministic: each turn, the faster pokemon attacks first with the move expected to
+// deal the most damage, accuracy included, until one of them faints.
+package battle
+
+import (
+	"fmt"
+
+	"github.com/JeanLeonHenry/pokedex/api"
+	"github.com/JeanLeonHenry/pokedex/typechart"
+)
+
+// MaxTurns ends battles where neither pokemon can hurt the other.
+const MaxTurns = 100
+
+// MaxLevel is the highest level a pokemon can reach.
+const MaxLevel = 100
+
+// Struggle is used by pokemon without any damaging move.
+var Struggle = Move{Name: "struggle", Power: 50, Accuracy: 100, DamageClass: "physical"}
+
+// Move is a damaging move, as used in battle.
+type Move struct {
+	Name        string
+	Type        string
+	Power       int
+	Accuracy    int
+	DamageClass string
+}
+
+// NewMove returns the battle form of a move, and false for moves that don't deal damage.
+func NewMove(move api.Move) (Move, bool) {
+	if move.Power == nil || *move.Power == 0 || move.DamageClass.Name == "status" {
+		return Move{}, false
+	}
+	accuracy := 100
+	if move.Accuracy != nil {
+		accuracy = *move.Accuracy
+	}
+	return Move{Name: move.Name, Type: move.Type.Name, Power: *move.Power, Accuracy: accuracy, DamageClass: move.DamageClass.Name}, true
+}
+
+// Pokemon is a pokemon taking part in a battle.
+type Pokemon struct {
+	Name  string
+	Level int
+	Types []string
+	// Stats are the base values, by name.
+	Stats map[string]int
+	Moves []Move
+}
+
+// NewPokemon returns a pokemon at the given level, clamped between 1 and MaxLevel, with the given moves.
+func NewPokemon(details api.PokemonDetails, level int, moves []Move) Pokemon {
+	stats := make(map[string]int)
+	for _, stat := range details.Stats {
+		stats[stat.Stat.Name] = stat.BaseStat
+	}
+	return Pokemon{Name: details.Name, Level: min(max(level, 1), MaxLevel), Types: details.TypesIn(""), Stats: stats, Moves: moves}
+}
+
+// stat returns the value of a stat at the pokemon's level, without individual values or effort.
+func (p Pokemon) stat(name string) int {
+	value := 2 * p.Stats[name] * p.Level / 100
+	if name == "hp" {
+		return value + p.Level + 10
+	}
+	return value + 5
+}
+
+// Result is the outcome of a battle.
+type Result struct {
+	// Winner is the index of the winning pokemon, or -1 on a draw.
+	Winner int
+	Log    []string
+}
+
+// Simulate runs a battle between two pokemon.
+func Simulate(first, second Pokemon, chart typechart.Chart) Result {
+	pokemons := [2]Pokemon{first, second}
+	hp := [2]int{first.stat("hp"), second.stat("hp")}
+	var log []string
+	for turn := 1; turn <= MaxTurns; turn++ {
+		order := [2]int{0, 1}
+		if second.stat("speed") > first.stat("speed") {
+			order = [2]int{1, 0}
+		}
+		for _, attacker := range order {
+			defender := 1 - attacker
+			move, damage, multiplier := bestMove(pokemons[attacker], pokemons[defender], chart)
+			hp[defender] = max(hp[defender]-damage, 0)
+			line := fmt.Sprintf("Turn %v: %v used %v!", turn, pokemons[attacker].Name, move.Name)
+			switch {
+			case multiplier == 0:
+				line += " It had no effect..."
+			case multiplier > 1:
+				line += " It's super effective!"
+			case multiplier < 1:
+				line += " It's not very effective..."
+			}
+			line += fmt.Sprintf(" %v lost %v HP (%v left).", pokemons[defender].Name, damage, hp[defender])
+			log = append(log, line)
+			if hp[defender] == 0 {
+				log = append(log, fmt.Sprintf("%v fainted!", pokemons[defender].Name))
+				return Result{Winner: attacker, Log: log}
+			}
+		}
+	}
+	log = append(log, "Neither pokemon could win.")
+	return Result{Winner: -1, Log: log}
+}
+
+// bestMove picks the move of attacker expected to deal the most damage to defender.
+func bestMove(attacker, defender Pokemon, chart typechart.Chart) (best Move, damage int, multiplier float64) {
+	moves := attacker.Moves
+	if len(moves) == 0 {
+		moves = []Move{Struggle}
+	}
+	damage = -1
+	for _, move := range moves {
+		d, m := Damage(attacker, defender, move, chart)
+		if d > damage {
+			best, damage, multiplier = move, d, m
+		}
+	}
+	return best, damage, multiplier
+}
+
+// Damage returns the damage dealt by attacker using move on defender, weighted by the move's accuracy,
+// along with the type multiplier. Struggle and typeless moves are always neutral.
+func Damage(attacker, defender Pokemon, move Move, chart typechart.Chart) (damage int, multiplier float64) {
+	attack, defense := attacker.stat("attack"), defender.stat("defense")
+	if move.DamageClass == "special" {
+		attack, defense = attacker.stat("special-attack"), defender.stat("special-defense")
+	}
+	base := (2*attacker.Level/5+2)*move.Power*attack/max(defense, 1)/50 + 2
+
+	modifier := 1.
+	multiplier = 1.
+	if move.Type != "" {
+		multiplier = chart.Multiplier(move.Type, defender.Types...)
+		for _, t := range attacker.Types {
+			if t == move.Type {
+				// same type attack bonus
+				modifier *= 1.5
+			}
+		}
+	}
+	modifier *= multiplier * float64(move.Accuracy) / 100
+	return int(float64(base) * modifier), multiplier
+}
+
+// ExperienceYield returns the experience gained by defeating a pokemon with the given base experience and level.
+func ExperienceYield(baseExperience, level int) int {
+	return max(baseExperience*level/7, 1)
+}
@@ -0,0 +1,70 @@
+package battle
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/JeanLeonHenry/pokedex/api"
+	"github.com/JeanLeonHenry/pokedex/typechart"
+)
+
+var chart = typechart.NewChart("", api.TypeDetails{
+	Name: "water",
+	DamageRelations: api.DamageRelations{
+		DoubleDamageFrom: []api.NamedResource{{Name: "electric"}, {Name: "grass"}},
+		HalfDamageFrom:   []api.NamedResource{{Name: "fire"}, {Name: "water"}},
+	},
+})
+
+func stats(hp, attack, defense, specialAttack, specialDefense, speed int) map[string]int {
+	return map[string]int{
+		"hp": hp, "attack": attack, "defense": defense,
+		"special-attack": specialAttack, "special-defense": specialDefense, "speed": speed,
+	}
+}
+
+var (
+	thunderbolt = Move{Name: "thunderbolt", Type: "electric", Power: 90, Accuracy: 100, DamageClass: "special"}
+	quickAttack = Move{Name: "quick-attack", Type: "normal", Power: 40, Accuracy: 100, DamageClass: "physical"}
+	waterGun    = Move{Name: "water-gun", Type: "water", Power: 40, Accuracy: 100, DamageClass: "special"}
+	pikachu     = Pokemon{Name: "pikachu", Level: 20, Types: []string{"electric"}, Stats: stats(35, 55, 40, 50, 50, 90), Moves: []Move{quickAttack, thunderbolt}}
+	squirtle    = Pokemon{Name: "squirtle", Level: 20, Types: []string{"water"}, Stats: stats(44, 48, 65, 50, 64, 43), Moves: []Move{waterGun}}
+)
+
+func TestDamage(t *testing.T) {
+	damage, multiplier := Damage(pikachu, squirtle, thunderbolt, chart)
+	if multiplier != 2 {
+		t.Errorf("expected thunderbolt to be super effective, got %vx", multiplier)
+	}
+	neutral, _ := Damage(pikachu, squirtle, quickAttack, chart)
+	if damage <= neutral {
+		t.Errorf("expected thunderbolt (%v) to deal more than quick-attack (%v)", damage, neutral)
+	}
+}
+
+func TestSimulate(t *testing.T) {
+	result := Simulate(squirtle, pikachu, chart)
+	if result.Winner != 1 {
+		t.Errorf("expected pikachu to win, got %v", result.Log)
+	}
+	if first := result.Log[0]; !strings.HasPrefix(first, "Turn 1: pikachu used thunderbolt!") {
+		t.Errorf("expected faster pikachu to open with thunderbolt, got %q", first)
+	}
+	// battles are deterministic
+	again := Simulate(squirtle, pikachu, chart)
+	if len(again.Log) != len(result.Log) || again.Log[len(again.Log)-1] != result.Log[len(result.Log)-1] {
+		t.Errorf("expected the same battle twice")
+	}
+}
+
+func TestDraw(t *testing.T) {
+	chart := typechart.NewChart("", api.TypeDetails{Name: "ghost", DamageRelations: api.DamageRelations{
+		NoDamageFrom: []api.NamedResource{{Name: "normal"}},
+	}})
+	tackle := Move{Name: "tackle", Type: "normal", Power: 40, Accuracy: 100, DamageClass: "physical"}
+	gastly := Pokemon{Name: "gastly", Level: 10, Types: []string{"ghost"}, Stats: stats(30, 35, 30, 100, 35, 80), Moves: []Move{tackle}}
+	haunter := Pokemon{Name: "haunter", Level: 10, Types: []string{"ghost"}, Stats: stats(45, 50, 45, 115, 55, 95), Moves: []Move{tackle}}
+	if result := Simulate(gastly, haunter, chart); result.Winner != -1 {
+		t.Errorf("expected a draw, got %v", result.Log[len(result.Log)-1])
+	}
+}
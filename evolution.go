@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/JeanLeonHenry/pokedex/api"
+)
+
+func (c *config) printEvolutions(ctx context.Context, args ...string) {
+	if len(args) != 1 {
+		log.Println("usage: evolutions <pokemon>")
+		return
+	}
+	pokemonName := args[0]
+	details, err := c.getPokemon(ctx, pokemonName)
+	if errors.Is(err, api.ErrNotFound) {
+		fmt.Println("No such pokemon:", pokemonName)
+		return
+	} else if err != nil {
+		printError(err)
+		return
+	}
+	species, err := c.getSpecies(ctx, details)
+	if err != nil {
+		printError(err)
+		return
+	}
+	chain, err := c.getEvolutionChain(ctx, species)
+	if err != nil {
+		printError(err)
+		return
+	}
+	if len(chain.Chain.EvolvesTo) == 0 {
+		fmt.Println(pokemonName, "doesn't evolve.")
+		return
+	}
+	fmt.Print(chain.Chain)
+}
+
+func (c *config) evolvePokemon(ctx context.Context, args ...string) {
+	if len(args) < 1 || len(args) > 2 {
+		log.Println("usage: evolve <pokemon> [evolution]")
+		return
+	}
+	pokemonName := args[0]
+	caught, ok, err := c.getCaught(ctx, pokemonName)
+	if !ok {
+		fmt.Println("No", pokemonName, "in pokedex.")
+		return
+	} else if err != nil {
+		printError(err)
+		return
+	}
+	species, err := c.getSpecies(ctx, caught.PokemonDetails)
+	if err != nil {
+		printError(err)
+		return
+	}
+	chain, err := c.getEvolutionChain(ctx, species)
+	if err != nil {
+		printError(err)
+		return
+	}
+	link, ok := chain.Chain.Find(species.Name)
+	if !ok || len(link.EvolvesTo) == 0 {
+		fmt.Println(pokemonName, "doesn't evolve.")
+		return
+	}
+
+	candidates := link.EvolvesTo
+	if len(args) == 2 {
+		candidates, ok = evolutionCandidates(link, args[1])
+		if !ok {
+			fmt.Println(pokemonName, "can't evolve into", args[1])
+			return
+		}
+	}
+	for _, candidate := range candidates {
+		if len(candidate.EvolutionDetails) == 0 {
+			continue
+		}
+		var reasons []string
+		for _, detail := range candidate.EvolutionDetails {
+			reason := c.unmetCondition(caught, detail)
+			if reason == "" {
+				c.evolveInto(ctx, pokemonName, caught, candidate, detail)
+				return
+			}
+			reasons = append(reasons, reason)
+		}
+		fmt.Printf("%v can't evolve into %v yet: %v.\n", pokemonName, candidate.Species.Name, strings.Join(reasons, ", or "))
+	}
+}
+
+// evolutionCandidates returns the direct evolution of link into target, if there's one. Evolutions
+// further down the chain don't count, they need the intermediate form first.
+func evolutionCandidates(link *api.ChainLink, target string) ([]api.ChainLink, bool) {
+	for _, evolution := range link.EvolvesTo {
+		if evolution.Species.Name == target {
+			return []api.ChainLink{evolution}, true
+		}
+	}
+	return nil, false
+}
+
+// unmetCondition explains why the pokemon can't evolve following the given detail, if it can't.
+// Only levels, the time of day and items from the inventory are tracked, other conditions are never met.
+func (c *config) unmetCondition(caught *CaughtPokemon, detail api.EvolutionDetail) string {
+	if untracked(detail) || (detail.Trigger.Name != "level-up" && detail.Trigger.Name != "use-item") {
+		return "needs " + detail.String() + ", which your pokedex can't track"
+	}
+	if detail.MinLevel != nil && caught.Level() < *detail.MinLevel {
+		return fmt.Sprint("needs level ", *detail.MinLevel)
+	}
+	if detail.TimeOfDay != "" && detail.TimeOfDay != timeOfDay(time.Now()) {
+		return "needs to be " + detail.TimeOfDay
+	}
+	if detail.Item != nil && !c.trainer.Inventory.Has(detail.Item.Name) {
+		return "needs a " + detail.Item.Name
+	}
+	return ""
+}
+
+// untracked reports whether detail has conditions other than a level, the time of day and an item.
+func untracked(detail api.EvolutionDetail) bool {
+	return detail.Gender != nil || detail.HeldItem != nil || detail.KnownMove != nil || detail.KnownMoveType != nil ||
+		detail.Location != nil || detail.MinHappiness != nil || detail.MinBeauty != nil || detail.MinAffection != nil ||
+		detail.NeedsOverworldRain || detail.PartySpecies != nil || detail.PartyType != nil ||
+		detail.RelativePhysicalStats != nil || detail.TradeSpecies != nil || detail.TurnUpsideDown
+}
+
+// timeOfDay returns the time of day as PokeAPI names it.
+func timeOfDay(t time.Time) string {
+	switch hour := t.Hour(); {
+	case hour >= 6 && hour < 17:
+		return "day"
+	case hour == 17:
+		return "dusk"
+	default:
+		return "night"
+	}
+}
+
+// evolveInto replaces the caught pokemon by the default form of the evolved species.
+func (c *config) evolveInto(ctx context.Context, pokemonName string, caught *CaughtPokemon, evolution api.ChainLink, detail api.EvolutionDetail) {
+	var species api.PokemonSpecies
+	if err := getResource[api.PokemonSpecies](ctx, c, evolution.Species.URL, &species, c.client.GetPokemonSpecies); err != nil {
+		printError(err)
+		return
+	}
+	variety, ok := species.DefaultVariety()
+	if !ok {
+		fmt.Println("No pokemon for species", species.Name)
+		return
+	}
+	evolved, err := c.getPokemon(ctx, variety.Name)
+	if err != nil {
+		printError(err)
+		return
+	}
+	// the pokedex holds a single pokemon per name, which mustn't be overwritten
+	if _, ok := c.trainer.Pokedex[evolved.Name]; ok {
+		fmt.Println(pokemonName, "can't evolve: you already have a", evolved.Name)
+		return
+	}
+	if detail.Item != nil {
+		c.trainer.Inventory.Use(detail.Item.Name)
+	}
+	fmt.Println("What?", pokemonName, "is evolving!")
+	caught.Evolve(evolved)
+	delete(c.trainer.Pokedex, pokemonName)
+	c.trainer.Pokedex[evolved.Name] = caught
+	fmt.Println("Congratulations! Your", pokemonName, "evolved into", evolved.Name, "!")
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// interrupter turns signals into the cancellation of the running command, if any, and into a request
+// to quit: on SIGTERM, or on an interrupt while no command is running.
+type interrupter struct {
+	mu       sync.Mutex
+	cancel   context.CancelFunc
+	quit     chan struct{}
+	quitOnce sync.Once
+}
+
+func newInterrupter() *interrupter {
+	return &interrupter{quit: make(chan struct{})}
+}
+
+func (i *interrupter) watch(sigs <-chan os.Signal) {
+	for sig := range sigs {
+		i.mu.Lock()
+		running := i.cancel != nil
+		if running {
+			i.cancel()
+			i.cancel = nil
+		}
+		i.mu.Unlock()
+		// the REPL prints the next prompt
+		fmt.Println()
+		if !running || sig != os.Interrupt {
+			i.stop()
+		}
+	}
+}
+
+// stop asks the REPL to quit once the running command, if any, returns.
+func (i *interrupter) stop() {
+	i.quitOnce.Do(func() { close(i.quit) })
+}
+
+// quitting returns a channel closed once the REPL should quit.
+func (i *interrupter) quitting() <-chan struct{} {
+	return i.quit
+}
+
+// start returns the context of a new command, and a func to call once the command returns.
+func (i *interrupter) start() (context.Context, func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	i.mu.Lock()
+	i.cancel = cancel
+	i.mu.Unlock()
+	return ctx, func() {
+		i.mu.Lock()
+		i.cancel = nil
+		i.mu.Unlock()
+		cancel()
+	}
+}
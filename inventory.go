@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+// Inventory counts the items of the trainer, by name.
+type Inventory map[string]int
+
+// defaultBall is thrown when catching a pokemon, unless told otherwise.
+const defaultBall = "poke-ball"
+
+// ballModifiers are the catch rate modifiers of the balls, by item name.
+var ballModifiers = map[string]float64{
+	"poke-ball":   1,
+	"great-ball":  1.5,
+	"ultra-ball":  2,
+	"master-ball": 255,
+}
+
+// newInventory returns the inventory of a new trainer.
+func newInventory() Inventory {
+	return Inventory{"poke-ball": 10, "great-ball": 3}
+}
+
+// catchProbability returns the odds of catching a pokemon at full hp with the given capture rate,
+// from 3 to 255, using the given ball.
+func catchProbability(captureRate int, ball string) float64 {
+	rate := float64(captureRate) * ballModifiers[ball] / 3
+	return min(rate/255, 1)
+}
+
+// Has reports whether there's at least one of the given item.
+func (inv Inventory) Has(item string) bool {
+	return inv[item] > 0
+}
+
+// Use consumes one of the given item, and reports whether there was one.
+func (inv Inventory) Use(item string) bool {
+	if !inv.Has(item) {
+		return false
+	}
+	inv[item]--
+	if inv[item] == 0 {
+		delete(inv, item)
+	}
+	return true
+}
+
+// Add stores qty of the given item.
+func (inv Inventory) Add(item string, qty int) {
+	inv[item] += qty
+}
+
+func (inv Inventory) String() (result string) {
+	items := make([]string, 0, len(inv))
+	for item := range inv {
+		items = append(items, item)
+	}
+	slices.Sort(items)
+	for _, item := range items {
+		result += fmt.Sprintf("\t- %v x%v\n", item, inv[item])
+	}
+	return result
+}
+
+func (c *config) printInventory(context.Context, ...string) {
+	fmt.Println("Money:", formatMoney(c.trainer.Money))
+	fmt.Print("Your inventory:\n", c.trainer.Inventory)
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestInventoryUse(t *testing.T) {
+	inv := Inventory{"poke-ball": 1}
+	if !inv.Use("poke-ball") {
+		t.Errorf("expected to use a poke-ball")
+	}
+	if inv.Use("poke-ball") || inv.Has("poke-ball") {
+		t.Errorf("expected no poke-ball left")
+	}
+	if _, ok := inv["poke-ball"]; ok {
+		t.Errorf("expected used up items to be removed")
+	}
+}
+
+func TestCatchProbability(t *testing.T) {
+	cases := []struct {
+		captureRate int
+		ball        string
+		want        float64
+	}{
+		// mewtwo
+		{captureRate: 3, ball: "poke-ball", want: 1. / 255},
+		{captureRate: 3, ball: "master-ball", want: 1},
+		// pikachu
+		{captureRate: 190, ball: "poke-ball", want: 190. / 3 / 255},
+		{captureRate: 190, ball: "ultra-ball", want: 2 * 190. / 3 / 255},
+		// caterpie
+		{captureRate: 255, ball: "great-ball", want: 0.5},
+	}
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
+			if got := catchProbability(c.captureRate, c.ball); got != c.want {
+				t.Errorf("expected %v, got %v", c.want, got)
+			}
+		})
+	}
+}
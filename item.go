@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/JeanLeonHenry/pokedex/api"
+)
+
+// getItem gets the details of the given item.
+func (c *config) getItem(ctx context.Context, itemName string) (item api.Item, err error) {
+	err = getResource[api.Item](ctx, c, c.client.ItemURL(itemName), &item, c.client.GetItem)
+	return item, err
+}
+
+func (c *config) printItem(ctx context.Context, args ...string) {
+	if len(args) != 1 {
+		log.Println("usage: item <name>")
+		return
+	}
+	itemName := args[0]
+	item, err := c.getItem(ctx, itemName)
+	if errors.Is(err, api.ErrNotFound) {
+		fmt.Println("No such item:", itemName)
+		return
+	} else if err != nil {
+		printError(err)
+		return
+	}
+	fmt.Printf("Name: %v\n", item.Name)
+	fmt.Printf("Category: %v\n", item.Category.Name)
+	fmt.Printf("Cost: %v\n", item.Cost)
+	if item.FlingPower != nil {
+		fmt.Printf("Fling power: %v\n", *item.FlingPower)
+	}
+	if effect := item.Effect(c.language); effect != "" {
+		fmt.Printf("Effect: %v\n", effect)
+	}
+
+	berryName, ok := item.BerryName()
+	if !ok {
+		return
+	}
+	var berry api.Berry
+	err = getResource[api.Berry](ctx, c, c.client.BerryURL(berryName), &berry, c.client.GetBerry)
+	if errors.Is(err, api.ErrNotFound) {
+		return
+	} else if err != nil {
+		printError(err)
+		return
+	}
+	fmt.Print(berry)
+}
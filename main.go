@@ -2,14 +2,21 @@ package main
 
 import (
 	"bufio"
-	"encoding/json"
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
 	"math/rand"
 	urls "net/url"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/JeanLeonHenry/pokedex/api"
@@ -19,7 +26,7 @@ import (
 type command struct {
 	name        string
 	description string
-	fn          func(...string)
+	fn          func(context.Context, ...string)
 }
 
 func (c command) String() string {
@@ -28,7 +35,29 @@ func (c command) String() string {
 
 var cmds map[string]command
 
-func displayHelp(...string) {
+// parseArgs splits the arguments of a command into positional ones and the values of --name options,
+// which must be among the given names.
+func parseArgs(args []string, names ...string) (positional []string, options map[string]string, err error) {
+	options = make(map[string]string)
+	for i := 0; i < len(args); i++ {
+		name, ok := strings.CutPrefix(args[i], "--")
+		if !ok {
+			positional = append(positional, args[i])
+			continue
+		}
+		if !slices.Contains(names, name) {
+			return nil, nil, fmt.Errorf("unknown option --%v", name)
+		}
+		if i+1 == len(args) {
+			return nil, nil, fmt.Errorf("missing value for --%v", name)
+		}
+		i++
+		options[name] = args[i]
+	}
+	return positional, options, nil
+}
+
+func displayHelp(context.Context, ...string) {
 	fmt.Println(`Pokedex
 
 Usage: pokedex <command>
@@ -40,58 +69,127 @@ Commands:`)
 	}
 }
 
-func notImplemented(...string) {
+func notImplemented(context.Context, ...string) {
 	fmt.Println("Not implemented")
 }
 
-type Pokedex map[string]api.PokemonDetails
-
-func (p Pokedex) String() (result string) {
-	for name := range p {
-		result += fmt.Sprintln("\t-", name)
-	}
-	return result
-}
-
 type config struct {
 	next     string
 	previous string
-	cache    pokecache.Cache
-	pokedex  Pokedex
+	client   *api.Client
+	cache    *pokecache.Cache
+	trainer  *Trainer
+	// profile names the trainer being played, whose save file is loaded on start and written on exit
+	profile string
+	// language of the texts shown from PokeAPI, e.g. flavor texts
+	language string
 }
 
-func getResource[T any](c *config, resource string, response *T, getter func(string) T) {
-	if data, ok := c.cache.Get(resource); !ok {
-		*response = getter(resource)
-		dataToCache, err := json.Marshal(*response)
-		if err != nil {
-			log.Fatalln("Error: couldn't cache response for ", resource)
-		}
-		c.cache.Add(resource, dataToCache)
-	} else {
-		err := json.Unmarshal(data, response)
+// getResource gets resource from the cache, or else from the getter. Stale cache entries are
+// returned right away, and refreshed in the background.
+func getResource[T any](ctx context.Context, c *config, resource string, response *T, getter func(context.Context, string) (T, error)) error {
+	fetched, err := pokecache.NewTypedCache[T](c.cache).Fetch(ctx, resource, func(ctx context.Context) (T, error) {
+		return getter(ctx, resource)
+	})
+	if err != nil {
+		return err
+	}
+	*response = fetched
+	return nil
+}
+
+// getResources gets every resource concurrently, in order, failing if any of them fails.
+func getResources[T any](ctx context.Context, c *config, resources []string, getter func(context.Context, string) (T, error)) ([]T, error) {
+	responses := make([]T, len(resources))
+	errs := make([]error, len(resources))
+	var wg sync.WaitGroup
+	for i, resource := range resources {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = getResource[T](ctx, c, resource, &responses[i], getter)
+		}()
+	}
+	wg.Wait()
+	for _, err := range errs {
 		if err != nil {
-			log.Fatalln("Error: couldn't unpack cache entry for ", resource)
+			return nil, err
 		}
 	}
+	return responses, nil
+}
+
+// getPokemon gets the details of the given pokemon, caught or not.
+func (c *config) getPokemon(ctx context.Context, pokemonName string) (details api.PokemonDetails, err error) {
+	err = getResource[api.PokemonDetails](ctx, c, c.client.PokemonURL(pokemonName), &details, c.client.GetPokemonDetails)
+	return details, err
 }
 
-func (c *config) printLocations(url string) {
+// getCaught returns the given pokemon from the pokedex, fetching its details if it was loaded from a
+// save, which only keeps its name. ok is false if there's no such pokemon in the pokedex.
+func (c *config) getCaught(ctx context.Context, pokemonName string) (caught *CaughtPokemon, ok bool, err error) {
+	caught, ok = c.trainer.Pokedex[pokemonName]
+	if !ok || caught.fetched {
+		return caught, ok, nil
+	}
+	details, err := c.getPokemon(ctx, caught.Name)
+	if err != nil {
+		return caught, true, err
+	}
+	caught.PokemonDetails = details
+	caught.fetched = true
+	return caught, true, nil
+}
+
+// getSpecies gets the species of the given pokemon.
+func (c *config) getSpecies(ctx context.Context, details api.PokemonDetails) (species api.PokemonSpecies, err error) {
+	err = getResource[api.PokemonSpecies](ctx, c, details.Species.URL, &species, c.client.GetPokemonSpecies)
+	return species, err
+}
+
+// getEvolutionChain gets the evolution chain the given species belongs to.
+func (c *config) getEvolutionChain(ctx context.Context, species api.PokemonSpecies) (chain api.EvolutionChain, err error) {
+	err = getResource[api.EvolutionChain](ctx, c, species.EvolutionChain.URL, &chain, c.client.GetEvolutionChain)
+	return chain, err
+}
+
+// printError reports a failed command, staying quiet about the cause if the user interrupted it.
+func printError(err error) {
+	if errors.Is(err, context.Canceled) {
+		fmt.Println("Cancelled.")
+		return
+	}
+	log.Println("Error:", err)
+}
+
+func (c *config) printLocations(ctx context.Context, url string) {
 	if url == "" {
 		fmt.Println("Can't go back from first page.")
 		return
 	}
 	var response api.LocationAreaResponse
-	getResource[api.LocationAreaResponse](c, url, &response, api.GetLocationsPage)
+	if err := getResource[api.LocationAreaResponse](ctx, c, url, &response, c.client.GetLocationsPage); err != nil {
+		printError(err)
+		return
+	}
 	c.previous = response.Previous
 	c.next = response.Next
 	fmt.Println(response.Results)
-	current, _ := urls.Parse(url)
-	offset, _ := strconv.Atoi(current.Query()["offset"][0])
+	offset, _ := pageOffset(url)
 	fmt.Println("Results from", offset, "to", offset+19)
 }
 
-func (c *config) printPokemons(args ...string) {
+// pageOffset returns the offset of a page of location areas, from its url.
+func pageOffset(url string) (int, bool) {
+	parsed, err := urls.Parse(url)
+	if err != nil {
+		return 0, false
+	}
+	offset, err := strconv.Atoi(parsed.Query().Get("offset"))
+	return offset, err == nil
+}
+
+func (c *config) printPokemons(ctx context.Context, args ...string) {
 	if len(args) != 1 {
 		log.Println("usage: explore <location name>")
 		return
@@ -102,83 +200,273 @@ func (c *config) printPokemons(args ...string) {
 	fmt.Println("Exploring", locationName, "...")
 
 	var pokemons api.PokemonSlice
-	url := api.LocationAreaEndpoint + locationName
-	getResource[api.PokemonSlice](c, url, &pokemons, api.GetPokemonsInArea)
+	url := c.client.LocationAreaURL(locationName)
+	err := getResource[api.PokemonSlice](ctx, c, url, &pokemons, c.client.GetPokemonsInArea)
+	if errors.Is(err, api.ErrNotFound) {
+		fmt.Println("No such location:", locationName)
+		return
+	} else if err != nil {
+		printError(err)
+		return
+	}
 	fmt.Println("Found Pokemon:")
 	fmt.Println(pokemons)
 }
 
-func (c *config) tryCatchPokemon(args ...string) {
-	if len(args) != 1 {
-		log.Println("usage: catch <pokemon>")
+func (c *config) tryCatchPokemon(ctx context.Context, args ...string) {
+	positional, options, err := parseArgs(args, "ball")
+	if err != nil || len(positional) != 1 {
+		log.Println("usage: catch <pokemon> [--ball <ball>]")
+		return
+	}
+	pokemonName := positional[0]
+	ball, ok := options["ball"]
+	if !ok {
+		ball = defaultBall
+	}
+	if _, ok := ballModifiers[ball]; !ok {
+		fmt.Println("No such ball:", ball)
+		return
+	}
+	if !c.trainer.Inventory.Has(ball) {
+		fmt.Println("No", ball, "left.")
 		return
 	}
-	pokemonName := args[0]
-	fmt.Println("Catching", pokemonName, "...")
 	// if pokemon not cached, get details
-	var details api.PokemonDetails
-	url := api.PokemonEndpoint + pokemonName
-	getResource[api.PokemonDetails](c, url, &details, api.GetPokemonDetails)
+	details, err := c.getPokemon(ctx, pokemonName)
+	if errors.Is(err, api.ErrNotFound) {
+		fmt.Println("No such pokemon:", pokemonName)
+		return
+	} else if err != nil {
+		printError(err)
+		return
+	}
+	// catching it again would throw away the experience and evolutions of the one caught
+	if _, ok := c.trainer.Pokedex[details.Name]; ok {
+		fmt.Println("You already caught a", details.Name)
+		return
+	}
+	species, err := c.getSpecies(ctx, details)
+	if err != nil {
+		printError(err)
+		return
+	}
 
 	// attempt catching pokemon
-	if rand.ExpFloat64()*50 > float64(details.BaseExperience) {
+	c.trainer.Inventory.Use(ball)
+	fmt.Println("Throwing a", ball, "at", pokemonName, "...")
+	if rand.Float64() < catchProbability(species.CaptureRate, ball) {
 		// if successfully caught, add to Pokedex
-		c.pokedex[pokemonName] = details
-		fmt.Println("Caught a lvl", details.BaseExperience, pokemonName, "!")
+		caught := newCaughtPokemon(details)
+		c.trainer.Pokedex[details.Name] = caught
+		reward := catchReward(details)
+		c.trainer.Earn(reward)
+		fmt.Println("Caught a lvl", caught.Level(), pokemonName, "!", "Earned", formatMoney(reward))
 	} else {
-		fmt.Println("A lvl", details.BaseExperience, pokemonName, "escaped !")
+		fmt.Println("A lvl", wildLevel(details), pokemonName, "escaped !")
 	}
 }
 
-func (c *config) inspectPokemon(args ...string) {
+func (c *config) inspectPokemon(ctx context.Context, args ...string) {
 	if len(args) != 1 {
 		fmt.Println("usage: inspect <pokemon>")
 		return
 	}
 	pokemonName := args[0]
-	if details, ok := c.pokedex[pokemonName]; !ok {
+	details, ok, err := c.getCaught(ctx, pokemonName)
+	if !ok {
 		fmt.Println("No", pokemonName, "in pokedex.")
-	} else {
-		fmt.Println(details)
+		return
+	} else if err != nil {
+		printError(err)
+		return
+	}
+	fmt.Print(details)
+
+	species, err := c.getSpecies(ctx, details.PokemonDetails)
+	if err != nil {
+		printError(err)
+		return
 	}
+	fmt.Printf("Genus: %v\n", species.Genus(c.language))
+	fmt.Printf("Capture rate: %v\n", species.CaptureRate)
+	if species.IsLegendary {
+		fmt.Println("Legendary!")
+	} else if species.IsMythical {
+		fmt.Println("Mythical!")
+	}
+	fmt.Println(species.FlavorText(c.language))
 }
 
-func (c *config) Next(...string) {
-	c.printLocations(c.next)
+func (c *config) manageCache(_ context.Context, args ...string) {
+	usage := "usage: cache stats | cache list | cache clear | cache evict <key>"
+	if len(args) == 0 {
+		fmt.Println(usage)
+		return
+	}
+	switch {
+	case args[0] == "stats" && len(args) == 1:
+		stats := c.cache.Stats()
+		ratio := 0.
+		if lookups := stats.Hits + stats.Misses; lookups > 0 {
+			ratio = float64(stats.Hits) / float64(lookups) * 100
+		}
+		fmt.Printf("Entries: %v (%v bytes)\n", stats.Entries, stats.Bytes)
+		fmt.Printf("Hits: %v, misses: %v (%.0f%% hit rate)\n", stats.Hits, stats.Misses, ratio)
+		fmt.Printf("Evictions: %v, expirations: %v\n", stats.Evictions, stats.Expirations)
+	case args[0] == "list" && len(args) == 1:
+		fmt.Print(c.cache)
+	case args[0] == "clear" && len(args) == 1:
+		c.cache.Clear()
+		fmt.Println("Cache cleared.")
+	case args[0] == "evict" && len(args) == 2:
+		if c.cache.Evict(args[1]) {
+			fmt.Println("Evicted", args[1])
+		} else {
+			fmt.Println("No", args[1], "in cache.")
+		}
+	default:
+		fmt.Println(usage)
+	}
+}
+
+func (c *config) Next(ctx context.Context, _ ...string) {
+	c.printLocations(ctx, c.next)
+}
+func (c *config) Prev(ctx context.Context, _ ...string) {
+	c.printLocations(ctx, c.previous)
 }
-func (c *config) Prev(...string) {
-	c.printLocations(c.previous)
+
+// defaultCacheDir returns the pokedex directory in the user cache dir, if there's one.
+func defaultCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "pokedex")
+}
+
+// newCache returns a cache persisted in dir, falling back to memory if dir is empty or unusable.
+func newCache(dir string, ttl time.Duration, options ...pokecache.Option) *pokecache.Cache {
+	if dir == "" {
+		return pokecache.NewCache(ttl, options...)
+	}
+	cache, err := pokecache.NewDiskCache(dir, ttl, options...)
+	if err != nil {
+		log.Println("Error: couldn't load cache, caching in memory only:", err)
+		return pokecache.NewCache(ttl, options...)
+	}
+	return cache
 }
 
 func main() {
 	// Set up
+	baseURL := flag.String("api", api.DefaultBaseURL, "base url of the PokeAPI instance to query")
+	timeout := flag.Duration("timeout", 10*time.Second, "give up on a PokeAPI request after this long")
+	attempts := flag.Int("attempts", api.DefaultRetryPolicy.MaxAttempts, "attempts at a PokeAPI request before giving up")
+	rate := flag.Float64("rate", api.DefaultRateLimit.PerSecond, "maximum PokeAPI requests per second, 0 for no limit")
+	cacheDir := flag.String("cache-dir", defaultCacheDir(), "directory of the persistent cache, empty to only cache in memory")
+	cacheTTL := flag.Duration("cache-ttl", 24*time.Hour, "how long PokeAPI responses are cached, 0 to refresh them every time they're shown")
+	cacheStale := flag.Duration("cache-stale", 7*24*time.Hour, "how long past -cache-ttl a PokeAPI response is still shown while being refreshed")
+	cacheMaxBytes := flag.Int("cache-max-bytes", 64<<20, "evict least recently used PokeAPI responses past this size, 0 for no limit")
+	language := flag.String("lang", api.DefaultLanguage, "language of the pokedex entries, e.g. fr or ja")
+	profile := flag.String("profile", defaultProfile, "trainer profile to play, created if there's none by that name")
+	flag.Parse()
+	if *cacheTTL < 0 || *cacheStale < 0 {
+		log.Fatal("-cache-ttl and -cache-stale can't be negative")
+	}
+	retry := api.DefaultRetryPolicy
+	retry.MaxAttempts = *attempts
+	client := api.NewClient(
+		api.WithBaseURL(*baseURL),
+		api.WithTimeout(*timeout),
+		api.WithRetry(retry),
+		api.WithRateLimit(api.RateLimit{PerSecond: *rate, Burst: api.DefaultRateLimit.Burst}),
+	)
 	cfg := &config{
-		next:     api.LocationAreaFirstPage,
-		previous: api.LocationAreaFirstPage,
-		cache:    *pokecache.NewCache(20 * time.Second),
-		pokedex:  make(Pokedex),
+		next:     client.LocationAreaFirstPage(),
+		previous: client.LocationAreaFirstPage(),
+		client:   client,
+		cache:    newCache(*cacheDir, *cacheTTL, pokecache.WithStaleTTL(*cacheStale), pokecache.WithMaxBytes(*cacheMaxBytes)),
+		trainer:  newTrainer(),
+		profile:  *profile,
+		language: *language,
+	}
+	if !profileName.MatchString(cfg.profile) {
+		log.Fatal("A profile name may only have letters, digits, - and _.")
+	}
+	if err := cfg.autoload(); err != nil {
+		// starting afresh would overwrite the save on exit, the save must be fixed or moved first
+		log.Fatal("Error: couldn't load save: ", err)
 	}
+	// Ctrl-C cancels the running command instead of killing the program. At the prompt, it quits
+	// like exit, Ctrl-D or SIGTERM do: saving and flushing the cache first
+	interrupts := newInterrupter()
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	go interrupts.watch(sigs)
 	cmds = map[string]command{
-		"map":     {name: "map", description: "Display next 20 locations.", fn: cfg.Next},
-		"mapb":    {name: "mapb", description: "Display previous 20 locations.", fn: cfg.Prev},
-		"explore": {name: "explore <location>", description: "List pokemons in the given location.", fn: cfg.printPokemons},
-		"help":    {name: "help", description: "Display help message.", fn: displayHelp},
-		"exit":    {name: "exit", description: "Quit program.", fn: func(...string) { os.Exit(0) }},
-		"catch":   {name: "catch <pokemon>", description: "Try and catch given pokemon.", fn: cfg.tryCatchPokemon},
-		"inspect": {name: "inspect <pokemon>", description: "Show details on the given pokemon from your pokedex.", fn: cfg.inspectPokemon},
-		"pokedex": {name: "pokedex", description: "List every caught pokemon.", fn: func(s ...string) { fmt.Println("Your Pokedex:\n", cfg.pokedex) }},
-	}
-	// REPL
-	scanner := bufio.NewScanner(os.Stdin)
+		"map":        {name: "map", description: "Display next 20 locations.", fn: cfg.Next},
+		"mapb":       {name: "mapb", description: "Display previous 20 locations.", fn: cfg.Prev},
+		"explore":    {name: "explore <location>", description: "List pokemons in the given location.", fn: cfg.printPokemons},
+		"help":       {name: "help", description: "Display help message.", fn: displayHelp},
+		"exit":       {name: "exit", description: "Save and quit program.", fn: func(context.Context, ...string) { interrupts.stop() }},
+		"save":       {name: "save [file]", description: "Save your pokedex, inventory and map position, to the save of your profile by default.", fn: cfg.saveCommand},
+		"load":       {name: "load [file]", description: "Load a save, replacing the current one. Defaults to the save of your profile.", fn: cfg.loadCommand},
+		"catch":      {name: "catch <pokemon> [--ball <ball>]", description: "Try and catch given pokemon, throwing a poke-ball unless told otherwise.", fn: cfg.tryCatchPokemon},
+		"inspect":    {name: "inspect <pokemon>", description: "Show details on the given pokemon from your pokedex.", fn: cfg.inspectPokemon},
+		"evolutions": {name: "evolutions <pokemon>", description: "Show how the given pokemon evolves.", fn: cfg.printEvolutions},
+		"evolve":     {name: "evolve <pokemon> [evolution]", description: "Evolve the given pokemon from your pokedex, if it meets the conditions.", fn: cfg.evolvePokemon},
+		"weakness":   {name: "weakness <pokemon> [--generation <generation>]", description: "List the types the given pokemon is weak or resistant to.", fn: cfg.printWeaknesses},
+		"battle":     {name: "battle <mine> <opponent>", description: "Battle a pokemon from your pokedex against another one, caught or wild.", fn: cfg.startBattle},
+		"moves":      {name: "moves <pokemon> [--version-group <version group>] [--method <learn method>]", description: "List the moves the given pokemon learns, by level-up in its latest version group by default.", fn: cfg.printMoves},
+		"ability":    {name: "ability <name>", description: "Show the effect of the given ability, and every pokemon that can have it.", fn: cfg.printAbility},
+		"item":       {name: "item <name>", description: "Show details on the given item, or berry.", fn: cfg.printItem},
+		"inventory":  {name: "inventory", description: "List your money and the items in your bag.", fn: cfg.printInventory},
+		"shop":       {name: "shop <list|buy <item> [qty]|sell <item>>", description: "Buy and sell items at PokeAPI prices.", fn: cfg.shop},
+		"profile":    {name: "profile <list|new <name>|switch <name>>", description: "List, create or switch trainer profiles. Profiles share the cache.", fn: cfg.manageProfiles},
+		"cache":      {name: "cache <stats|list|clear|evict <key>>", description: "Show or manage cached PokeAPI responses.", fn: cfg.manageCache},
+		"pokedex":    {name: "pokedex", description: "List every caught pokemon.", fn: func(context.Context, ...string) { fmt.Println("Your Pokedex:\n", cfg.trainer.Pokedex) }},
+	}
+	repl(interrupts)
+
+	// Shut down
+	signal.Stop(sigs)
+	cfg.autosave()
+	// waits for the refreshes in flight to be cancelled, so that the persistent cache is left consistent
+	cfg.cache.Close()
+}
+
+// repl runs commands read from stdin, one at a time, until told to quit or stdin is closed.
+func repl(interrupts *interrupter) {
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		if err := scanner.Err(); err != nil {
+			log.Println("Error: couldn't read input:", err)
+		}
+	}()
 	for {
 		// TODO: add some cli niceties
 		// - up/down should navigate a cmd history
 		// - tab should complete on possible cmds
 		fmt.Print("pokedex > ")
-		if ok := scanner.Scan(); !ok {
-			log.Fatal("Wrong input. Quitting.")
+		var input string
+		select {
+		case <-interrupts.quitting():
+			return
+		case line, ok := <-lines:
+			if !ok {
+				// Ctrl-D
+				fmt.Println()
+				return
+			}
+			input = line
 		}
-		input := scanner.Text()
 		args := strings.Fields(strings.TrimSpace(input))
 		if len(args) == 0 {
 			log.Println("Wrong command.")
@@ -188,7 +476,15 @@ func main() {
 			log.Println("Wrong command.")
 			continue
 		} else {
-			cmd.fn(args[1:]...)
+			ctx, done := interrupts.start()
+			cmd.fn(ctx, args[1:]...)
+			done()
+		}
+		// don't run another command once told to quit
+		select {
+		case <-interrupts.quitting():
+			return
+		default:
 		}
 	}
 }
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	"github.com/JeanLeonHenry/pokedex/api"
+)
+
+func (c *config) printMoves(ctx context.Context, args ...string) {
+	positional, options, err := parseArgs(args, "version-group", "method")
+	if err != nil || len(positional) != 1 {
+		log.Println("usage: moves <pokemon> [--version-group <version group>] [--method <learn method>]")
+		return
+	}
+	pokemonName := positional[0]
+	details, err := c.getPokemon(ctx, pokemonName)
+	if errors.Is(err, api.ErrNotFound) {
+		fmt.Println("No such pokemon:", pokemonName)
+		return
+	} else if err != nil {
+		printError(err)
+		return
+	}
+	versionGroup, ok := options["version-group"]
+	if !ok {
+		versionGroup = details.LatestVersionGroup()
+	}
+	method, ok := options["method"]
+	if !ok {
+		method = "level-up"
+	}
+
+	learnable := details.LearnableMoves(versionGroup, method)
+	if len(learnable) == 0 {
+		fmt.Println(pokemonName, "learns no move by", method, "in", versionGroup)
+		return
+	}
+	urls := make([]string, len(learnable))
+	for i, learned := range learnable {
+		urls[i] = learned.URL
+	}
+	moves, err := getResources[api.Move](ctx, c, urls, c.client.GetMove)
+	if err != nil {
+		printError(err)
+		return
+	}
+	fmt.Printf("%v learns by %v in %v:\n", pokemonName, method, versionGroup)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Lvl\tMove\tType\tClass\tPower\tAcc\tPP")
+	for i, move := range moves {
+		fmt.Fprintf(w, "%v\t%v\n", learnable[i].Level, move)
+	}
+	w.Flush()
+}
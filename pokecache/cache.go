@@ -1,6 +1,8 @@
 package pokecache
 
 import (
+	"container/list"
+	"context"
 	"fmt"
 	"strings"
 	"sync"
@@ -11,48 +13,362 @@ type Cache struct {
 	data     map[string]cacheEntry
 	mu       *sync.Mutex
 	interval time.Duration
+	// staleTTL is how long past interval an entry may still be served, flagged as stale.
+	staleTTL time.Duration
+	store    store
+	// pending are the writes to the store not done yet, in order. They're done outside of mu by a
+	// single writer goroutine, running while writing is set, which signals idle once done.
+	pending []storeOp
+	writing bool
+	idle    *sync.Cond
+	// recency lists keys from most to least recently used.
+	recency    *list.List
+	size       int
+	maxBytes   int
+	maxEntries int
+	stats      Stats
+	done       chan struct{}
+	// reaped is closed once the reaper returns.
+	reaped  chan struct{}
+	closed  bool
+	flights *flights
+	// background is the context of the refreshes of stale entries, cancelled by Close.
+	background context.Context
+	cancel     context.CancelFunc
+}
+
+// storeOp is a pending write to the store: saving an entry, or removing it.
+type storeOp struct {
+	key    string
+	entry  cacheEntry
+	remove bool
+}
+
+// Stats counts what happened to a cache since it was created.
+type Stats struct {
+	Hits   int
+	Misses int
+	// Evictions counts entries dropped to stay within budget.
+	Evictions int
+	// Expirations counts entries dropped for being older than their ttl and stale ttl.
+	Expirations int
+	Entries     int
+	Bytes       int
+}
+
+// EntryInfo describes an entry of the cache.
+type EntryInfo struct {
+	Key  string
+	Age  time.Duration
+	Size int
 }
 
 type cacheEntry struct {
 	createdAt time.Time
-	val       []byte
+	ttl       time.Duration
+	staleTTL  time.Duration
+	// val is the encoded value, obj the decoded one of a TypedCache entry. Either may be missing.
+	val  []byte
+	obj  any
+	elem *list.Element
+}
+
+// stale reports whether the entry outlived its ttl, and should be refreshed.
+func (e cacheEntry) stale() bool {
+	return time.Since(e.createdAt) > e.ttl
+}
+
+// expired reports whether the entry outlived its stale ttl too, and should be dropped.
+func (e cacheEntry) expired() bool {
+	return time.Since(e.createdAt) > e.ttl+e.staleTTL
+}
+
+// entrySize is what an entry counts against the byte budget of the cache.
+func entrySize(key string, val []byte) int {
+	return len(key) + len(val)
 }
 
-func NewCache(interval time.Duration) *Cache {
-	cache := &Cache{interval: interval, data: make(map[string]cacheEntry), mu: &sync.Mutex{}}
-	go cache.reapLoop()
+// Option configures a Cache.
+type Option func(*Cache)
+
+// WithMaxBytes bounds the total size of the keys and values held by the cache.
+// Least recently used entries are evicted to stay within budget. Zero means no bound.
+func WithMaxBytes(n int) Option {
+	return func(c *Cache) { c.maxBytes = n }
+}
+
+// WithMaxEntries bounds the number of entries held by the cache.
+// Least recently used entries are evicted to stay within budget. Zero means no bound.
+func WithMaxEntries(n int) Option {
+	return func(c *Cache) { c.maxEntries = n }
+}
+
+// WithStaleTTL keeps entries for d past their interval. Lookup still returns them during that window,
+// but flagged as stale so that the caller may refresh them.
+func WithStaleTTL(d time.Duration) Option {
+	return func(c *Cache) { c.staleTTL = d }
+}
+
+// NewCache returns an in-memory cache whose entries are dropped once older than interval. With a
+// non-positive interval, entries are stale as soon as they are added, and only kept for their stale
+// ttl; nothing runs in the background to drop them, they are dropped when looked up.
+func NewCache(interval time.Duration, options ...Option) *Cache {
+	return newCache(interval, nil, options...)
+}
+
+func newCache(interval time.Duration, s store, options ...Option) *Cache {
+	cache := &Cache{
+		interval: interval,
+		data:     make(map[string]cacheEntry),
+		mu:       &sync.Mutex{},
+		store:    s,
+		recency:  list.New(),
+		done:     make(chan struct{}),
+		reaped:   make(chan struct{}),
+		flights:  &flights{calls: make(map[string]*call)},
+	}
+	cache.idle = sync.NewCond(cache.mu)
+	cache.background, cache.cancel = context.WithCancel(context.Background())
+	for _, option := range options {
+		option(cache)
+	}
+	if interval > 0 {
+		go cache.reapLoop()
+	} else {
+		close(cache.reaped)
+	}
 	return cache
 }
 
-func (c Cache) String() (result string) {
-	result = "{"
-	for key, entry := range c.data {
-		offset := strings.Split(strings.Split(key, "?")[1], "&")[0]
-		result += fmt.Sprintln(offset, entry.createdAt.Format(time.DateTime))
+func (c *Cache) String() string {
+	var result strings.Builder
+	for _, info := range c.List() {
+		fmt.Fprintf(&result, "%v (%v, %v bytes)\n", info.Key, info.Age.Round(time.Second), info.Size)
+	}
+	return result.String()
+}
+
+// Stats returns the counters of the cache.
+func (c *Cache) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := c.stats
+	stats.Entries = len(c.data)
+	stats.Bytes = c.size
+	return stats
+}
+
+// List describes every entry of the cache, from most to least recently used.
+func (c *Cache) List() []EntryInfo {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	infos := make([]EntryInfo, 0, len(c.data))
+	for elem := c.recency.Front(); elem != nil; elem = elem.Next() {
+		key := elem.Value.(string)
+		entry := c.data[key]
+		infos = append(infos, EntryInfo{Key: key, Age: time.Since(entry.createdAt), Size: entrySize(key, entry.val)})
+	}
+	return infos
+}
+
+// Evict drops the entry for key, and reports whether there was one.
+func (c *Cache) Evict(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.data[key]
+	c.remove(key)
+	return ok
+}
+
+// Clear drops every entry of the cache. Counters are kept.
+func (c *Cache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for c.recency.Len() > 0 {
+		c.remove(c.recency.Front().Value.(string))
 	}
-	return result + "}"
 }
 
 func (c *Cache) Add(key string, val []byte) {
+	c.add(key, val, nil)
+}
+
+// add caches val and obj for key. Entries without val aren't persisted.
+func (c *Cache) add(key string, val []byte, obj any) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	c.data[key] = cacheEntry{createdAt: time.Now(), val: val}
+	entry := cacheEntry{createdAt: time.Now(), ttl: c.interval, staleTTL: c.staleTTL, val: val, obj: obj}
+	c.insert(key, entry)
+	if val != nil {
+		c.queue(storeOp{key: key, entry: entry})
+	}
+	c.evict()
+}
+
+// needsEncoding reports whether typed entries should be added with their encoded value,
+// to be persisted or to count against the byte budget.
+func (c *Cache) needsEncoding() bool {
+	return c.store != nil || c.maxBytes > 0
 }
 func (c *Cache) Get(key string) ([]byte, bool) {
+	val, _, ok := c.Lookup(key)
+	return val, ok
+}
+
+// Lookup is Get, also reporting whether the entry is past its interval and within its stale ttl.
+func (c *Cache) Lookup(key string) (val []byte, stale bool, ok bool) {
+	entry, stale, ok := c.lookup(key)
+	return entry.val, stale, ok
+}
+
+func (c *Cache) lookup(key string) (entry cacheEntry, stale bool, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok = c.data[key]
+	// the reaper may not have caught up with this entry yet
+	if ok && entry.expired() {
+		c.remove(key)
+		c.stats.Expirations++
+		ok = false
+	}
+	if ok {
+		c.stats.Hits++
+		c.recency.MoveToFront(entry.elem)
+	} else {
+		c.stats.Misses++
+	}
+	return entry, ok && entry.stale(), ok
+}
+
+// memoize keeps obj as the decoded value of entry, if entry is still the one cached for key.
+func (c *Cache) memoize(key string, entry cacheEntry, obj any) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
+	current, ok := c.data[key]
+	if ok && current.elem == entry.elem {
+		current.obj = obj
+		c.data[key] = current
+	}
+}
+
+// insert adds entry as the most recently used one, replacing any previous entry for key.
+// c.mu must be held.
+func (c *Cache) insert(key string, entry cacheEntry) {
+	if old, ok := c.data[key]; ok {
+		c.size -= entrySize(key, old.val)
+		c.recency.Remove(old.elem)
+	}
+	entry.elem = c.recency.PushFront(key)
+	c.data[key] = entry
+	c.size += entrySize(key, entry.val)
+}
+
+// remove drops the entry for key from memory and from the store, if any. c.mu must be held.
+func (c *Cache) remove(key string) {
 	entry, ok := c.data[key]
-	return entry.val, ok
+	if !ok {
+		return
+	}
+	c.size -= entrySize(key, entry.val)
+	c.recency.Remove(entry.elem)
+	delete(c.data, key)
+	c.queue(storeOp{key: key, remove: true})
+}
+
+// queue schedules a write to the store, starting the writer if it isn't running. c.mu must be held.
+func (c *Cache) queue(op storeOp) {
+	if c.store == nil {
+		return
+	}
+	c.pending = append(c.pending, op)
+	if !c.writing {
+		c.writing = true
+		go c.write()
+	}
+}
+
+// write does the pending writes to the store, so that slow disks don't hold up lookups, until there
+// are none left.
+func (c *Cache) write() {
+	for {
+		c.mu.Lock()
+		ops := c.pending
+		c.pending = nil
+		if len(ops) == 0 {
+			c.writing = false
+			c.idle.Broadcast()
+			c.mu.Unlock()
+			return
+		}
+		c.mu.Unlock()
+		for _, op := range ops {
+			// a failed write only costs persistence
+			if op.remove {
+				c.store.remove(op.key)
+			} else {
+				c.store.save(op.key, op.entry)
+			}
+		}
+	}
+}
+
+// Flush waits for the entries added or dropped so far to be written to or removed from the store.
+func (c *Cache) Flush() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for c.writing {
+		c.idle.Wait()
+	}
+}
+
+// evict drops least recently used entries until the cache is within budget. c.mu must be held.
+func (c *Cache) evict() {
+	for c.recency.Len() > 0 &&
+		((c.maxBytes > 0 && c.size > c.maxBytes) || (c.maxEntries > 0 && len(c.data) > c.maxEntries)) {
+		c.remove(c.recency.Back().Value.(string))
+		c.stats.Evictions++
+	}
+}
+
+// Close stops the goroutine reaping expired entries, cancels the refreshes of stale entries and waits
+// for both to be done, along with any other fetch in flight, so that nothing is written to the cache
+// afterwards, then flushes it. The cache stays usable, expired entries are then only dropped when looked up.
+func (c *Cache) Close() {
+	c.mu.Lock()
+	if !c.closed {
+		// from now on, fetches aren't counted in running: counting them could race with Wait
+		c.closed = true
+		close(c.done)
+		c.cancel()
+	}
+	c.mu.Unlock()
+	<-c.reaped
+	c.flights.running.Wait()
+	c.Flush()
 }
+
 func (c *Cache) reapLoop() {
+	defer close(c.reaped)
 	ticker := time.NewTicker(c.interval)
-	for range ticker.C {
-		for key, entry := range c.data {
-			if time.Now().Sub(entry.createdAt) > c.interval {
-				c.mu.Lock()
-				delete(c.data, key)
-				c.mu.Unlock()
-			}
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C:
+			c.reap()
+		}
+	}
+}
+
+// reap drops every expired entry.
+func (c *Cache) reap() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, entry := range c.data {
+		if entry.expired() {
+			c.remove(key)
+			c.stats.Expirations++
 		}
 	}
 }
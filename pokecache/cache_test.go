@@ -2,6 +2,7 @@ package pokecache
 
 import (
 	"fmt"
+	"sync"
 	"testing"
 	"time"
 )
@@ -25,6 +26,7 @@ func TestAddGet(t *testing.T) {
 	for i, c := range cases {
 		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
 			cache := NewCache(interval)
+			defer cache.Close()
 			cache.Add(c.key, c.val)
 			val, ok := cache.Get(c.key)
 			if !ok {
@@ -43,6 +45,7 @@ func TestReapLoop(t *testing.T) {
 	const baseTime = 5 * time.Millisecond
 	const waitTime = baseTime + 5*time.Millisecond
 	cache := NewCache(baseTime)
+	defer cache.Close()
 	cache.Add("https://example.com", []byte("testdata"))
 
 	_, ok := cache.Get("https://example.com")
@@ -59,3 +62,141 @@ func TestReapLoop(t *testing.T) {
 		return
 	}
 }
+
+func TestLRUEviction(t *testing.T) {
+	const interval = 5 * time.Second
+	cases := []struct {
+		options []Option
+		evicted string
+	}{
+		{
+			options: []Option{WithMaxEntries(2)},
+			evicted: "https://example.com/b",
+		},
+		{
+			// every entry is 20 bytes
+			options: []Option{WithMaxBytes(50)},
+			evicted: "https://example.com/b",
+		},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
+			cache := NewCache(interval, c.options...)
+			defer cache.Close()
+			cache.Add("https://example.com/a", []byte("a"))
+			cache.Add("https://example.com/b", []byte("b"))
+			// a is now more recently used than b
+			cache.Get("https://example.com/a")
+			cache.Add("https://example.com/c", []byte("c"))
+
+			for _, key := range []string{"https://example.com/a", "https://example.com/b", "https://example.com/c"} {
+				_, ok := cache.Get(key)
+				if key == c.evicted && ok {
+					t.Errorf("expected %v to be evicted", key)
+				} else if key != c.evicted && !ok {
+					t.Errorf("expected to find %v", key)
+				}
+			}
+		})
+	}
+}
+
+func TestStats(t *testing.T) {
+	const interval = 5 * time.Second
+	cache := NewCache(interval, WithMaxEntries(1))
+	defer cache.Close()
+	cache.Add("https://example.com", []byte("testdata"))
+	cache.Get("https://example.com")
+	cache.Get("https://example.com/path")
+	cache.Add("https://example.com/path", []byte("moretestdata"))
+
+	stats := cache.Stats()
+	want := Stats{Hits: 1, Misses: 1, Evictions: 1, Entries: 1, Bytes: len("https://example.com/path") + len("moretestdata")}
+	if stats != want {
+		t.Errorf("expected %+v, got %+v", want, stats)
+	}
+
+	if !cache.Evict("https://example.com/path") {
+		t.Errorf("expected to evict key")
+	}
+	cache.Add("https://example.com", []byte("testdata"))
+	cache.Clear()
+	if entries := cache.List(); len(entries) != 0 {
+		t.Errorf("expected cache to be empty, found %v", entries)
+	}
+}
+
+func TestConcurrentAccess(t *testing.T) {
+	const baseTime = time.Millisecond
+	cache := NewCache(baseTime, WithMaxEntries(8))
+	defer cache.Close()
+
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range 200 {
+				key := fmt.Sprintf("https://example.com/%v", (i+j)%16)
+				cache.Add(key, []byte("testdata"))
+				cache.Get(key)
+				if j%50 == 0 {
+					cache.Stats()
+					cache.List()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestClose(t *testing.T) {
+	cache := NewCache(time.Millisecond)
+	cache.Close()
+	select {
+	case <-cache.reaped:
+	default:
+		t.Errorf("expected reaper to be stopped once Close returns")
+	}
+	// closing twice is fine
+	cache.Close()
+}
+
+func TestStaleLookup(t *testing.T) {
+	const baseTime = 5 * time.Millisecond
+	cache := NewCache(baseTime, WithStaleTTL(time.Hour))
+	defer cache.Close()
+	cache.Add("https://example.com", []byte("testdata"))
+
+	if _, stale, ok := cache.Lookup("https://example.com"); !ok || stale {
+		t.Errorf("expected to find fresh key")
+		return
+	}
+
+	time.Sleep(baseTime + 5*time.Millisecond)
+
+	val, stale, ok := cache.Lookup("https://example.com")
+	if !ok || !stale {
+		t.Errorf("expected to find stale key")
+		return
+	}
+	if string(val) != "testdata" {
+		t.Errorf("expected to find value")
+		return
+	}
+
+	cache.Add("https://example.com", []byte("refreshed"))
+	if _, stale, _ := cache.Lookup("https://example.com"); stale {
+		t.Errorf("expected refreshed key to be fresh")
+	}
+}
+
+func TestZeroInterval(t *testing.T) {
+	cache := NewCache(0, WithStaleTTL(time.Hour))
+	defer cache.Close()
+	cache.Add("https://example.com", []byte("testdata"))
+	if val, stale, ok := cache.Lookup("https://example.com"); !ok || !stale || string(val) != "testdata" {
+		t.Errorf("expected to find stale key, got %q", val)
+	}
+}
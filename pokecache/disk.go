@@ -0,0 +1,135 @@
+package pokecache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// store persists cache entries. A Cache without one only lives in memory.
+type store interface {
+	save(key string, entry cacheEntry) error
+	remove(key string) error
+	// load returns every readable entry, skipping corrupted ones.
+	load() (map[string]cacheEntry, error)
+}
+
+// diskStore keeps one json file per key in a directory.
+type diskStore struct {
+	dir string
+}
+
+// diskEntry is the on-disk form of a cacheEntry. The key is kept since file names are hashes.
+type diskEntry struct {
+	Key       string        `json:"key"`
+	CreatedAt time.Time     `json:"created_at"`
+	TTL       time.Duration `json:"ttl"`
+	StaleTTL  time.Duration `json:"stale_ttl"`
+	Val       []byte        `json:"val"`
+}
+
+const diskEntryExt = ".json"
+
+// NewDiskCache returns a cache persisted under dir, loaded with the entries of previous sessions
+// that haven't outlived the interval and stale ttl they were added with. Entries are written to disk
+// in the background as they are added, Flush or Close wait for them to be; a failed write only costs
+// persistence, the entry is still cached in memory.
+func NewDiskCache(dir string, interval time.Duration, options ...Option) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	s := &diskStore{dir: dir}
+	entries, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	// oldest first, so that they are the first evicted if the budget is too small for every entry
+	keys := make([]string, 0, len(entries))
+	for key, entry := range entries {
+		if entry.expired() {
+			s.remove(key)
+			continue
+		}
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b string) int { return entries[a].createdAt.Compare(entries[b].createdAt) })
+	cache := newCache(interval, s, options...)
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	for _, key := range keys {
+		cache.insert(key, entries[key])
+	}
+	cache.evict()
+	return cache, nil
+}
+
+func (s *diskStore) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:])+diskEntryExt)
+}
+
+func (s *diskStore) save(key string, entry cacheEntry) error {
+	data, err := json.Marshal(diskEntry{Key: key, CreatedAt: entry.createdAt, TTL: entry.ttl, StaleTTL: entry.staleTTL, Val: entry.val})
+	if err != nil {
+		return err
+	}
+	// write then rename, so that a crash never leaves a half-written entry behind
+	tmp, err := os.CreateTemp(s.dir, "tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path(key))
+}
+
+func (s *diskStore) remove(key string) error {
+	err := os.Remove(s.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func (s *diskStore) load() (map[string]cacheEntry, error) {
+	files, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	entries := make(map[string]cacheEntry)
+	for _, file := range files {
+		path := filepath.Join(s.dir, file.Name())
+		if strings.HasPrefix(file.Name(), "tmp-") {
+			// leftover of an interrupted save
+			os.Remove(path)
+			continue
+		}
+		if file.IsDir() || filepath.Ext(file.Name()) != diskEntryExt {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var entry diskEntry
+		if err := json.Unmarshal(data, &entry); err != nil || s.path(entry.Key) != path {
+			os.Remove(path)
+			continue
+		}
+		entries[entry.Key] = cacheEntry{createdAt: entry.CreatedAt, ttl: entry.TTL, staleTTL: entry.StaleTTL, val: entry.Val}
+	}
+	return entries, nil
+}
@@ -0,0 +1,94 @@
+package pokecache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDiskCachePersists(t *testing.T) {
+	const interval = 5 * time.Second
+	dir := t.TempDir()
+	cache, err := NewDiskCache(dir, interval)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer cache.Close()
+	cache.Add("https://example.com", []byte("testdata"))
+	cache.Flush()
+
+	reopened, err := NewDiskCache(dir, interval)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer reopened.Close()
+	val, ok := reopened.Get("https://example.com")
+	if !ok {
+		t.Errorf("expected to find key")
+		return
+	}
+	if string(val) != "testdata" {
+		t.Errorf("expected to find value")
+		return
+	}
+}
+
+func TestDiskCacheSkipsExpiredAndCorrupted(t *testing.T) {
+	const baseTime = 5 * time.Millisecond
+	dir := t.TempDir()
+	cache, err := NewDiskCache(dir, baseTime)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer cache.Close()
+	cache.Add("https://example.com", []byte("testdata"))
+	cache.Flush()
+	if err := os.WriteFile(filepath.Join(dir, "garbage.json"), []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(baseTime + 5*time.Millisecond)
+
+	reopened, err := NewDiskCache(dir, time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer reopened.Close()
+	if _, ok := reopened.Get("https://example.com"); ok {
+		t.Errorf("expected to not find key")
+	}
+	files, _ := os.ReadDir(dir)
+	if len(files) != 0 {
+		t.Errorf("expected expired and corrupted entries to be removed, found %v files", len(files))
+	}
+}
+
+// blockingStore is a store whose writes hang until released.
+type blockingStore struct {
+	release chan struct{}
+}
+
+func (s blockingStore) save(string, cacheEntry) error        { <-s.release; return nil }
+func (s blockingStore) remove(string) error                  { <-s.release; return nil }
+func (s blockingStore) load() (map[string]cacheEntry, error) { return nil, nil }
+
+func TestSlowStoreDoesntBlockLookups(t *testing.T) {
+	store := blockingStore{release: make(chan struct{})}
+	cache := newCache(5*time.Second, store)
+	defer cache.Close()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		cache.Add("https://example.com", []byte("testdata"))
+		cache.Add("https://example.org", []byte("testdata"))
+		cache.Get("https://example.com")
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Errorf("expected cache to be usable while the store is writing")
+	}
+	close(store.release)
+}
@@ -0,0 +1,97 @@
+package pokecache
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// FetchFunc produces the value of a missing or stale key.
+type FetchFunc func(ctx context.Context) ([]byte, error)
+
+// call is a fetch in flight, shared by every caller asking for the same key meanwhile.
+type call struct {
+	done chan struct{}
+	val  any
+	err  error
+	// joined counts the callers that found the call in flight, guarded by flights.mu.
+	joined int
+}
+
+// flights tracks the fetches in flight, by key.
+type flights struct {
+	mu    sync.Mutex
+	calls map[string]*call
+	// running counts the fetches in flight, for Close to wait on. Fetches started once the cache is
+	// closed aren't counted.
+	running sync.WaitGroup
+}
+
+// Fetch returns the entry for key, calling fetch and adding its result on a miss. Concurrent calls
+// for the same key share a single call to fetch, and a single Add. A stale entry is returned right
+// away, and refreshed in the background with a context that isn't cancelled along with ctx, but by Close.
+func (c *Cache) Fetch(ctx context.Context, key string, fetch FetchFunc) ([]byte, error) {
+	val, err := c.fetch(ctx, key,
+		func() (any, bool, bool) { return c.Lookup(key) },
+		func(ctx context.Context) (any, error) { return fetch(ctx) },
+		func(val any) { c.Add(key, val.([]byte)) },
+	)
+	data, _ := val.([]byte)
+	return data, err
+}
+
+// fetch implements Fetch for any kind of value, read from the cache by lookup and written by add.
+func (c *Cache) fetch(ctx context.Context, key string, lookup func() (any, bool, bool), fetch func(context.Context) (any, error), add func(any)) (any, error) {
+	val, stale, ok := lookup()
+	if ok {
+		if stale {
+			c.start(c.background, key, fetch, add)
+		}
+		return val, nil
+	}
+	for {
+		flight := c.start(ctx, key, fetch, add)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-flight.done:
+		}
+		// the caller that started the shared fetch gave up, but this one didn't
+		if errors.Is(flight.err, context.Canceled) && ctx.Err() == nil {
+			continue
+		}
+		return flight.val, flight.err
+	}
+}
+
+// start returns the fetch in flight for key, starting one if there's none.
+func (c *Cache) start(ctx context.Context, key string, fetch func(context.Context) (any, error), add func(any)) *call {
+	c.flights.mu.Lock()
+	defer c.flights.mu.Unlock()
+	if flight, ok := c.flights.calls[key]; ok {
+		flight.joined++
+		return flight
+	}
+	flight := &call{done: make(chan struct{})}
+	c.flights.calls[key] = flight
+	c.mu.Lock()
+	counted := !c.closed
+	if counted {
+		c.flights.running.Add(1)
+	}
+	c.mu.Unlock()
+	go func() {
+		if counted {
+			defer c.flights.running.Done()
+		}
+		flight.val, flight.err = fetch(ctx)
+		if flight.err == nil {
+			add(flight.val)
+		}
+		c.flights.mu.Lock()
+		delete(c.flights.calls, key)
+		c.flights.mu.Unlock()
+		close(flight.done)
+	}()
+	return flight
+}
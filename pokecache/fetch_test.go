@@ -0,0 +1,150 @@
+package pokecache
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// waitJoined waits for n callers to join the fetch in flight for key.
+func waitJoined(t *testing.T, cache *Cache, key string, n int) {
+	t.Helper()
+	for deadline := time.Now().Add(time.Second); ; time.Sleep(time.Millisecond) {
+		cache.flights.mu.Lock()
+		joined := 0
+		if flight, ok := cache.flights.calls[key]; ok {
+			joined = flight.joined
+		}
+		cache.flights.mu.Unlock()
+		if joined >= n {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %v callers to join the fetch, got %v", n, joined)
+		}
+	}
+}
+
+func TestFetchCoalesces(t *testing.T) {
+	const interval = 5 * time.Second
+	cache := NewCache(interval)
+	defer cache.Close()
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	fetch := func(ctx context.Context) ([]byte, error) {
+		calls.Add(1)
+		<-release
+		return []byte("testdata"), nil
+	}
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			val, err := cache.Fetch(context.Background(), "https://example.com", fetch)
+			if err != nil || string(val) != "testdata" {
+				t.Errorf("expected to fetch value, got %q: %v", val, err)
+			}
+		}()
+	}
+	// every caller but the one that started the fetch joins it
+	waitJoined(t, cache, "https://example.com", 9)
+	close(release)
+	wg.Wait()
+
+	if n := calls.Load(); n != 1 {
+		t.Errorf("expected a single fetch, got %v", n)
+	}
+	if stats := cache.Stats(); stats.Entries != 1 {
+		t.Errorf("expected a single entry, got %v", stats.Entries)
+	}
+}
+
+func TestFetchRefreshesStale(t *testing.T) {
+	const baseTime = 5 * time.Millisecond
+	cache := NewCache(baseTime, WithStaleTTL(time.Hour))
+	defer cache.Close()
+	cache.Add("https://example.com", []byte("testdata"))
+
+	time.Sleep(baseTime + 5*time.Millisecond)
+
+	refreshed := make(chan struct{})
+	ctx, cancel := context.WithCancel(context.Background())
+	val, err := cache.Fetch(ctx, "https://example.com", func(ctx context.Context) ([]byte, error) {
+		defer close(refreshed)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return []byte("refreshed"), nil
+	})
+	// the refresh must survive the caller going away
+	cancel()
+	if err != nil || string(val) != "testdata" {
+		t.Errorf("expected stale value, got %q: %v", val, err)
+	}
+
+	<-refreshed
+	// Add happens right after fetch returns, and Close waits for it
+	cache.Close()
+	val, stale, ok := cache.Lookup("https://example.com")
+	if !ok || stale || string(val) != "refreshed" {
+		t.Errorf("expected refreshed value, got %q", val)
+	}
+}
+
+func TestFetchOutlivesCancelledLeader(t *testing.T) {
+	const interval = 5 * time.Second
+	cache := NewCache(interval)
+	defer cache.Close()
+
+	leaderCtx, cancel := context.WithCancel(context.Background())
+	started := make(chan struct{})
+	go cache.Fetch(leaderCtx, "https://example.com", func(ctx context.Context) ([]byte, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	<-started
+
+	done := make(chan struct{})
+	var val []byte
+	var err error
+	go func() {
+		defer close(done)
+		val, err = cache.Fetch(context.Background(), "https://example.com", func(ctx context.Context) ([]byte, error) {
+			return []byte("testdata"), nil
+		})
+	}()
+	waitJoined(t, cache, "https://example.com", 1)
+	cancel()
+	<-done
+	if err != nil || string(val) != "testdata" {
+		t.Errorf("expected to fetch value, got %q: %v", val, err)
+	}
+}
+
+func TestCloseCancelsRefresh(t *testing.T) {
+	const baseTime = 5 * time.Millisecond
+	cache := NewCache(baseTime, WithStaleTTL(time.Hour))
+	cache.Add("https://example.com", []byte("testdata"))
+
+	time.Sleep(baseTime + 5*time.Millisecond)
+
+	started := make(chan struct{})
+	cache.Fetch(context.Background(), "https://example.com", func(ctx context.Context) ([]byte, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	<-started
+	// Close returns only once the refresh gave up
+	cache.Close()
+	val, _, ok := cache.Lookup("https://example.com")
+	if !ok || string(val) != "testdata" {
+		t.Errorf("expected stale value to be kept, got %q", val)
+	}
+}
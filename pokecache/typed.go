@@ -0,0 +1,91 @@
+package pokecache
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Codec encodes the values of a TypedCache, for the disk backend and the byte budget.
+type Codec[T any] struct {
+	Encode func(T) ([]byte, error)
+	Decode func([]byte) (T, error)
+}
+
+// JSONCodec encodes values as json.
+func JSONCodec[T any]() Codec[T] {
+	return Codec[T]{
+		Encode: func(val T) ([]byte, error) { return json.Marshal(val) },
+		Decode: func(data []byte) (val T, err error) {
+			err = json.Unmarshal(data, &val)
+			return val, err
+		},
+	}
+}
+
+// TypedCache is a view of a Cache holding decoded values of type T. Values are only encoded when the
+// underlying cache persists them or has a byte budget, and only decoded once, when first read back
+// from disk. Typed caches over the same Cache share its budget, so their keys shouldn't collide.
+//
+// Values aren't copied: every caller gets the very value that was cached, which must be treated as
+// read-only, including whatever its slices, maps and pointers refer to.
+type TypedCache[T any] struct {
+	cache *Cache
+	codec Codec[T]
+}
+
+// NewTypedCache returns a view of cache holding values of type T, encoded as json.
+func NewTypedCache[T any](cache *Cache) *TypedCache[T] {
+	return NewTypedCacheWithCodec(cache, JSONCodec[T]())
+}
+
+// NewTypedCacheWithCodec returns a view of cache holding values of type T, encoded by codec.
+func NewTypedCacheWithCodec[T any](cache *Cache, codec Codec[T]) *TypedCache[T] {
+	return &TypedCache[T]{cache: cache, codec: codec}
+}
+
+// Add caches val for key. If val can't be encoded, it's only cached in memory.
+func (t *TypedCache[T]) Add(key string, val T) {
+	var data []byte
+	if t.cache.needsEncoding() {
+		data, _ = t.codec.Encode(val)
+	}
+	t.cache.add(key, data, val)
+}
+
+// Get returns the value cached for key, which mustn't be modified.
+func (t *TypedCache[T]) Get(key string) (T, bool) {
+	val, _, ok := t.Lookup(key)
+	return val, ok
+}
+
+// Lookup is Get, also reporting whether the entry is past its interval and within its stale ttl.
+// An entry that isn't a T and can't be decoded as one is reported missing.
+func (t *TypedCache[T]) Lookup(key string) (val T, stale bool, ok bool) {
+	entry, stale, ok := t.cache.lookup(key)
+	if !ok {
+		return val, false, false
+	}
+	if obj, isT := entry.obj.(T); isT {
+		return obj, stale, true
+	}
+	if entry.val == nil {
+		return val, false, false
+	}
+	val, err := t.codec.Decode(entry.val)
+	if err != nil {
+		return val, false, false
+	}
+	t.cache.memoize(key, entry, val)
+	return val, stale, true
+}
+
+// Fetch is Cache.Fetch for values of type T.
+func (t *TypedCache[T]) Fetch(ctx context.Context, key string, fetch func(context.Context) (T, error)) (T, error) {
+	val, err := t.cache.fetch(ctx, key,
+		func() (any, bool, bool) { return t.Lookup(key) },
+		func(ctx context.Context) (any, error) { return fetch(ctx) },
+		func(val any) { t.Add(key, val.(T)) },
+	)
+	typed, _ := val.(T)
+	return typed, err
+}
@@ -0,0 +1,66 @@
+package pokecache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type pokemon struct {
+	Name  string
+	Moves []string
+}
+
+func TestTypedCache(t *testing.T) {
+	const interval = 5 * time.Second
+	cache := NewCache(interval)
+	defer cache.Close()
+	pokemons := NewTypedCache[pokemon](cache)
+
+	fetches := 0
+	fetch := func(ctx context.Context) (pokemon, error) {
+		fetches++
+		return pokemon{Name: "pikachu", Moves: []string{"thunder-shock"}}, nil
+	}
+	for range 2 {
+		val, err := pokemons.Fetch(context.Background(), "https://example.com/pikachu", fetch)
+		if err != nil || val.Name != "pikachu" {
+			t.Errorf("expected to fetch pikachu, got %+v: %v", val, err)
+		}
+	}
+	if fetches != 1 {
+		t.Errorf("expected a single fetch, got %v", fetches)
+	}
+	// an in-memory cache without byte budget has no use for the encoded value
+	if data, _ := cache.Get("https://example.com/pikachu"); data != nil {
+		t.Errorf("expected value to not be encoded, got %s", data)
+	}
+}
+
+func TestTypedDiskCache(t *testing.T) {
+	const interval = 5 * time.Second
+	dir := t.TempDir()
+	cache, err := NewDiskCache(dir, interval)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer cache.Close()
+	NewTypedCache[pokemon](cache).Add("https://example.com/pikachu", pokemon{Name: "pikachu"})
+	cache.Flush()
+
+	reopened, err := NewDiskCache(dir, interval)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer reopened.Close()
+	pokemons := NewTypedCache[pokemon](reopened)
+	val, ok := pokemons.Get("https://example.com/pikachu")
+	if !ok || val.Name != "pikachu" {
+		t.Errorf("expected to find pikachu, got %+v", val)
+	}
+	// decoding the entry once is enough
+	entry, _, _ := reopened.lookup("https://example.com/pikachu")
+	if _, ok := entry.obj.(pokemon); !ok {
+		t.Errorf("expected decoded value to be kept")
+	}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/JeanLeonHenry/pokedex/api"
+	"github.com/JeanLeonHenry/pokedex/battle"
+)
+
+type Pokedex map[string]*CaughtPokemon
+
+func (p Pokedex) String() (result string) {
+	for name := range p {
+		result += fmt.Sprintln("\t-", name)
+	}
+	return result
+}
+
+// CaughtPokemon is a pokemon of the pokedex, along with what happened to it since it was caught.
+type CaughtPokemon struct {
+	api.PokemonDetails
+	Experience int
+	CaughtAt   time.Time
+	Evolutions []Evolution
+	// fetched tells whether PokemonDetails holds every detail, or only the name, as loaded from a save.
+	fetched bool
+}
+
+// savedPokemon is what's saved of a caught pokemon. Its details are fetched again on load, so that
+// saves stay small and don't change along with the PokeAPI types.
+type savedPokemon struct {
+	Name       string      `json:"name"`
+	Experience int         `json:"experience"`
+	CaughtAt   time.Time   `json:"caught_at"`
+	Evolutions []Evolution `json:"evolutions"`
+}
+
+func (p *CaughtPokemon) MarshalJSON() ([]byte, error) {
+	return json.Marshal(savedPokemon{Name: p.Name, Experience: p.Experience, CaughtAt: p.CaughtAt, Evolutions: p.Evolutions})
+}
+
+// UnmarshalJSON only sets the name of the pokemon details, see config.getCaught for the rest.
+func (p *CaughtPokemon) UnmarshalJSON(data []byte) error {
+	var saved savedPokemon
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return err
+	}
+	*p = CaughtPokemon{
+		PokemonDetails: api.PokemonDetails{Name: saved.Name},
+		Experience:     saved.Experience,
+		CaughtAt:       saved.CaughtAt,
+		Evolutions:     saved.Evolutions,
+	}
+	return nil
+}
+
+// Evolution records a caught pokemon evolving.
+type Evolution struct {
+	From string    `json:"from"`
+	To   string    `json:"to"`
+	At   time.Time `json:"at"`
+}
+
+// wildLevel is the level of a wild pokemon, which grows with the experience it's worth.
+func wildLevel(details api.PokemonDetails) int {
+	return max(details.BaseExperience/10, 1)
+}
+
+// experienceFor returns the experience needed to reach the given level, on a medium-fast growth rate.
+func experienceFor(level int) int {
+	return level * level * level
+}
+
+func newCaughtPokemon(details api.PokemonDetails) *CaughtPokemon {
+	return &CaughtPokemon{
+		PokemonDetails: details,
+		Experience:     experienceFor(wildLevel(details)),
+		CaughtAt:       time.Now(),
+		fetched:        true,
+	}
+}
+
+// Level returns the level the pokemon reached with its experience, up to battle.MaxLevel.
+func (p *CaughtPokemon) Level() int {
+	level := int(math.Cbrt(float64(p.Experience)))
+	// make up for floating point errors around perfect cubes
+	for experienceFor(level+1) <= p.Experience {
+		level++
+	}
+	return min(max(level, 1), battle.MaxLevel)
+}
+
+// GainExperience adds to the experience of the pokemon, and returns its level before and after.
+func (p *CaughtPokemon) GainExperience(experience int) (before, after int) {
+	before = p.Level()
+	// experience past the max level is lost, as in the games
+	p.Experience = min(p.Experience+experience, experienceFor(battle.MaxLevel))
+	return before, p.Level()
+}
+
+// Evolve replaces the pokemon with its evolved form, keeping its experience.
+func (p *CaughtPokemon) Evolve(evolved api.PokemonDetails) {
+	p.Evolutions = append(p.Evolutions, Evolution{From: p.Name, To: evolved.Name, At: time.Now()})
+	p.PokemonDetails = evolved
+	p.fetched = true
+}
+
+func (p *CaughtPokemon) String() (result string) {
+	result += p.PokemonDetails.String()
+	result += fmt.Sprintf("Level: %v (%v xp)\n", p.Level(), p.Experience)
+	result += fmt.Sprintf("Caught: %v\n", p.CaughtAt.Format(time.DateTime))
+	for _, evolution := range p.Evolutions {
+		result += fmt.Sprintf("Evolved from %v to %v: %v\n", evolution.From, evolution.To, evolution.At.Format(time.DateTime))
+	}
+	return result
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/JeanLeonHenry/pokedex/api"
+)
+
+func TestLevel(t *testing.T) {
+	cases := []struct {
+		experience int
+		level      int
+	}{
+		{experience: 0, level: 1},
+		{experience: 7, level: 1},
+		{experience: 8, level: 2},
+		{experience: 3374, level: 14},
+		{experience: 3375, level: 15},
+		{experience: 1_000_000, level: 100},
+		{experience: 2_000_000, level: 100},
+	}
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
+			p := CaughtPokemon{Experience: c.experience}
+			if level := p.Level(); level != c.level {
+				t.Errorf("expected level %v, got %v", c.level, level)
+			}
+		})
+	}
+}
+
+func TestUnmetCondition(t *testing.T) {
+	level := func(n int) *int { return &n }
+	cfg := &config{trainer: &Trainer{Inventory: Inventory{"thunder-stone": 1}}}
+	caught := &CaughtPokemon{Experience: experienceFor(20)}
+	cases := []struct {
+		detail api.EvolutionDetail
+		met    bool
+	}{
+		{detail: api.EvolutionDetail{Trigger: api.NamedResource{Name: "level-up"}, MinLevel: level(16)}, met: true},
+		{detail: api.EvolutionDetail{Trigger: api.NamedResource{Name: "level-up"}, MinLevel: level(36)}, met: false},
+		{detail: api.EvolutionDetail{Trigger: api.NamedResource{Name: "use-item"}, Item: &api.NamedResource{Name: "thunder-stone"}}, met: true},
+		{detail: api.EvolutionDetail{Trigger: api.NamedResource{Name: "use-item"}, Item: &api.NamedResource{Name: "moon-stone"}}, met: false},
+		{detail: api.EvolutionDetail{Trigger: api.NamedResource{Name: "trade"}}, met: false},
+		{detail: api.EvolutionDetail{Trigger: api.NamedResource{Name: "level-up"}, MinHappiness: level(220)}, met: false},
+		{detail: api.EvolutionDetail{Trigger: api.NamedResource{Name: "level-up"}, MinLevel: level(16), HeldItem: &api.NamedResource{Name: "metal-coat"}}, met: false},
+	}
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
+			reason := cfg.unmetCondition(caught, c.detail)
+			if (reason == "") != c.met {
+				t.Errorf("expected condition met to be %v, got reason %q", c.met, reason)
+			}
+		})
+	}
+}
+
+func TestEvolutionCandidates(t *testing.T) {
+	charmander := &api.ChainLink{
+		Species: api.PokemonSpeciesRef{Name: "charmander"},
+		EvolvesTo: []api.ChainLink{{
+			Species:   api.PokemonSpeciesRef{Name: "charmeleon"},
+			EvolvesTo: []api.ChainLink{{Species: api.PokemonSpeciesRef{Name: "charizard"}}},
+		}},
+	}
+	cases := []struct {
+		target string
+		ok     bool
+	}{
+		{target: "charmeleon", ok: true},
+		// a grandchild needs the intermediate evolution first
+		{target: "charizard", ok: false},
+		{target: "charmander", ok: false},
+		{target: "pikachu", ok: false},
+	}
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
+			candidates, ok := evolutionCandidates(charmander, c.target)
+			if ok != c.ok || (ok && candidates[0].Species.Name != c.target) {
+				t.Errorf("expected candidate %v to be found: %v, got %v", c.target, c.ok, candidates)
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// defaultProfile is the profile played unless told otherwise. It's kept in the default save file, so
+// that saves from before profiles carry over.
+const defaultProfile = "default"
+
+// profileName matches the names a profile may have, which are also file names.
+var profileName = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// profilePath returns the save file of a profile: the default save file for the default profile, and
+// profiles/<name>.json in the data dir for the others.
+func profilePath(profile string) (string, error) {
+	if profile == "" || profile == defaultProfile {
+		return savePath("")
+	}
+	dir, err := dataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "profiles", profile+".json"), nil
+}
+
+// profiles lists the names of the saved profiles, the default one first.
+func profiles() ([]string, error) {
+	var names []string
+	path, err := profilePath(defaultProfile)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(path); err == nil {
+		names = append(names, defaultProfile)
+	}
+	dir, err := dataDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(filepath.Join(dir, "profiles"))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".json")
+		if ok && !entry.IsDir() && profileName.MatchString(name) {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+func (c *config) manageProfiles(_ context.Context, args ...string) {
+	usage := "usage: profile list | profile new <name> | profile switch <name>"
+	switch {
+	case len(args) == 1 && args[0] == "list":
+		c.listProfiles()
+	case len(args) == 2 && args[0] == "new":
+		c.newProfile(args[1])
+	case len(args) == 2 && args[0] == "switch":
+		c.switchProfile(args[1])
+	default:
+		log.Println(usage)
+	}
+}
+
+func (c *config) listProfiles() {
+	names, err := profiles()
+	if err != nil {
+		printError(err)
+		return
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "\tProfile\tCaught\tMoney\tStarted")
+	// a profile isn't saved until the first exit or switch
+	if !slices.Contains(names, c.profile) {
+		names = append(names, c.profile)
+	}
+	for _, name := range names {
+		if name == c.profile {
+			// the save may lag behind
+			fmt.Fprintf(w, "*\t%v\t%v\n", name, trainerSummary(c.trainer))
+			continue
+		}
+		path, err := profilePath(name)
+		if err != nil {
+			printError(err)
+			return
+		}
+		save, err := readSave(path)
+		if err != nil || save.Trainer == nil {
+			fmt.Fprintf(w, "\t%v\t?\t?\t?\n", name)
+			continue
+		}
+		fmt.Fprintf(w, "\t%v\t%v\n", name, trainerSummary(save.Trainer))
+	}
+	w.Flush()
+}
+
+// trainerSummary formats the stats of a trainer as tab-separated columns.
+func trainerSummary(t *Trainer) string {
+	return fmt.Sprintf("%v\t%v\t%v", len(t.Pokedex), formatMoney(t.Money), t.StartedAt.Format(time.DateOnly))
+}
+
+// leaveProfile saves the current profile, before playing another one.
+func (c *config) leaveProfile() error {
+	path, err := profilePath(c.profile)
+	if err != nil {
+		return err
+	}
+	return c.writeSave(path)
+}
+
+func (c *config) newProfile(name string) {
+	if !profileName.MatchString(name) {
+		fmt.Println("A profile name may only have letters, digits, - and _.")
+		return
+	}
+	path, err := profilePath(name)
+	if err != nil {
+		printError(err)
+		return
+	}
+	if _, err := os.Stat(path); err == nil || name == c.profile {
+		fmt.Println("There's already a profile named", name)
+		return
+	}
+	if err := c.leaveProfile(); err != nil {
+		printError(err)
+		return
+	}
+	c.profile = name
+	c.trainer = newTrainer()
+	if err := c.writeSave(path); err != nil {
+		printError(err)
+		return
+	}
+	fmt.Println("Created profile", name)
+}
+
+func (c *config) switchProfile(name string) {
+	if name == c.profile {
+		fmt.Println("Already playing as", name)
+		return
+	}
+	if !profileName.MatchString(name) {
+		fmt.Println("No profile named", name)
+		return
+	}
+	path, err := profilePath(name)
+	if err != nil {
+		printError(err)
+		return
+	}
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		fmt.Println("No profile named", name)
+		return
+	}
+	if err := c.leaveProfile(); err != nil {
+		printError(err)
+		return
+	}
+	if err := c.loadSave(path); err != nil {
+		printError(err)
+		return
+	}
+	c.profile = name
+	fmt.Println("Switched to profile", name)
+}
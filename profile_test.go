@@ -0,0 +1,44 @@
+package main
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/JeanLeonHenry/pokedex/api"
+)
+
+func TestProfiles(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	cfg := newTestConfig(t)
+	cfg.profile = defaultProfile
+	cfg.trainer.Pokedex["pikachu"] = newCaughtPokemon(api.PokemonDetails{Name: "pikachu", BaseExperience: 112})
+
+	cfg.newProfile("misty")
+	if cfg.profile != "misty" || len(cfg.trainer.Pokedex) != 0 {
+		t.Fatalf("expected a fresh misty profile, got %v with %v", cfg.profile, cfg.trainer.Pokedex)
+	}
+	cfg.trainer.Pokedex["staryu"] = newCaughtPokemon(api.PokemonDetails{Name: "staryu", BaseExperience: 68})
+
+	cfg.switchProfile(defaultProfile)
+	if _, ok := cfg.trainer.Pokedex["pikachu"]; cfg.profile != defaultProfile || !ok {
+		t.Errorf("expected to get pikachu back, got %v", cfg.trainer.Pokedex)
+	}
+	cfg.switchProfile("misty")
+	if _, ok := cfg.trainer.Pokedex["staryu"]; cfg.profile != "misty" || !ok {
+		t.Errorf("expected misty to keep staryu, got %v", cfg.trainer.Pokedex)
+	}
+	// unknown or invalid profiles are left alone
+	cfg.switchProfile("brock")
+	cfg.newProfile("../brock")
+	if cfg.profile != "misty" {
+		t.Errorf("expected to stay on misty, got %v", cfg.profile)
+	}
+
+	names, err := profiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(names, []string{defaultProfile, "misty"}) {
+		t.Errorf("expected default and misty profiles, got %v", names)
+	}
+}
@@ -0,0 +1,274 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// saveVersion is the version of the save file schema written by this pokedex.
+const saveVersion = 2
+
+// defaultSaveName is the save file loaded on start and written on exit, in the data dir.
+const defaultSaveName = "save.json"
+
+// saveFile is the content of a save file.
+type saveFile struct {
+	Version int       `json:"version"`
+	SavedAt time.Time `json:"saved_at"`
+	Trainer *Trainer  `json:"trainer"`
+	// Next and Previous are the offsets of the pages map and mapb show, if any. Offsets rather than
+	// urls, so that a save carries over to another -api.
+	Next     *int `json:"next"`
+	Previous *int `json:"previous"`
+}
+
+// migrations upgrade a save file, decoded as a json object, to the next version: migrations[v-1]
+// upgrades a save from version v to v+1. A schema change bumps saveVersion and appends a migration.
+var migrations = []func(map[string]json.RawMessage) error{
+	// version 2 saves pages as offsets instead of urls. It also saves pokemon as names instead of
+	// details, which needs no migration: the details left in version 1 saves are ignored.
+	func(save map[string]json.RawMessage) error {
+		for _, key := range []string{"next", "previous"} {
+			var url string
+			if raw, ok := save[key]; ok {
+				if err := json.Unmarshal(raw, &url); err != nil {
+					return err
+				}
+			}
+			if offset, ok := pageOffset(url); ok {
+				save[key] = json.RawMessage(strconv.Itoa(offset))
+			} else {
+				delete(save, key)
+			}
+		}
+		return nil
+	},
+}
+
+// ErrNewerSave is returned when loading a save written by a more recent pokedex.
+var ErrNewerSave = errors.New("save file was written by a newer pokedex")
+
+// dataDir returns the pokedex directory in the XDG data dir.
+func dataDir() (string, error) {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return filepath.Join(dir, "pokedex"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".local", "share", "pokedex"), nil
+}
+
+// savePath resolves the name of a save file: bare names are kept in the data dir, paths are used as is.
+func savePath(name string) (string, error) {
+	if name == "" {
+		name = defaultSaveName
+	}
+	if filepath.Base(name) != name {
+		return name, nil
+	}
+	dir, err := dataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name), nil
+}
+
+// writeSave writes the state of c to path, atomically.
+func (c *config) writeSave(path string) error {
+	data, err := json.Marshal(saveFile{
+		Version:  saveVersion,
+		SavedAt:  time.Now(),
+		Trainer:  c.trainer,
+		Next:     savedPage(c.next),
+		Previous: savedPage(c.previous),
+	})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	// write then rename, so that a crash never leaves a half-written save behind
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".save-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// readSave reads the save file at path, migrating it to the current version.
+func readSave(path string) (save saveFile, err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return save, err
+	}
+	var header struct {
+		Version int `json:"version"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return save, fmt.Errorf("corrupted save file: %w", err)
+	}
+	if header.Version > saveVersion {
+		return save, ErrNewerSave
+	}
+	if header.Version < saveVersion {
+		if data, err = migrate(data, header.Version); err != nil {
+			return save, fmt.Errorf("couldn't migrate save file from version %v: %w", header.Version, err)
+		}
+	}
+	if err := json.Unmarshal(data, &save); err != nil {
+		return save, fmt.Errorf("corrupted save file: %w", err)
+	}
+	return save, nil
+}
+
+// migrate upgrades a save file of the given version to the current one.
+func migrate(data []byte, version int) ([]byte, error) {
+	if version < 1 || len(migrations) < saveVersion-1 {
+		return nil, fmt.Errorf("no migration from version %v", version)
+	}
+	var save map[string]json.RawMessage
+	if err := json.Unmarshal(data, &save); err != nil {
+		return nil, err
+	}
+	for v := version; v < saveVersion; v++ {
+		if err := migrations[v-1](save); err != nil {
+			return nil, err
+		}
+		save["version"] = json.RawMessage(fmt.Sprint(v + 1))
+	}
+	return json.Marshal(save)
+}
+
+// savedPage returns the offset of the page at url, or nil if there's no page.
+func savedPage(url string) *int {
+	offset, ok := pageOffset(url)
+	if !ok {
+		return nil
+	}
+	return &offset
+}
+
+// pageURL returns the url of the page saved by savedPage.
+func (c *config) pageURL(offset *int) string {
+	if offset == nil {
+		return ""
+	}
+	return c.client.LocationAreaPage(*offset)
+}
+
+// loadSave replaces the state of c with the save file at path. The details of the caught pokemon are
+// only fetched when needed, so that a save loads offline. Empty entries are dropped.
+func (c *config) loadSave(path string) error {
+	save, err := readSave(path)
+	if err != nil {
+		return err
+	}
+	if save.Trainer == nil {
+		save.Trainer = newTrainer()
+	}
+	if save.Trainer.Pokedex == nil {
+		save.Trainer.Pokedex = make(Pokedex)
+	}
+	if save.Trainer.Inventory == nil {
+		save.Trainer.Inventory = make(Inventory)
+	}
+	for name, caught := range save.Trainer.Pokedex {
+		if caught == nil || caught.Name == "" {
+			log.Println("Error: dropping empty pokedex entry", name)
+			delete(save.Trainer.Pokedex, name)
+		}
+	}
+	c.trainer = save.Trainer
+	if save.Next != nil || save.Previous != nil {
+		c.next, c.previous = c.pageURL(save.Next), c.pageURL(save.Previous)
+	}
+	return nil
+}
+
+func (c *config) saveCommand(_ context.Context, args ...string) {
+	if len(args) > 1 {
+		log.Println("usage: save [file]")
+		return
+	}
+	path, err := c.resolveSave(args)
+	if err == nil {
+		err = c.writeSave(path)
+	}
+	if err != nil {
+		printError(err)
+		return
+	}
+	fmt.Println("Saved to", path)
+}
+
+func (c *config) loadCommand(_ context.Context, args ...string) {
+	if len(args) > 1 {
+		log.Println("usage: load [file]")
+		return
+	}
+	path, err := c.resolveSave(args)
+	if err == nil {
+		err = c.loadSave(path)
+	}
+	if errors.Is(err, os.ErrNotExist) {
+		fmt.Println("No save at", path)
+		return
+	} else if err != nil {
+		printError(err)
+		return
+	}
+	fmt.Println("Loaded", path)
+}
+
+// autoload loads the save file of the current profile, if there's one.
+func (c *config) autoload() error {
+	path, err := profilePath(c.profile)
+	if err == nil {
+		err = c.loadSave(path)
+	}
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// autosave writes the save file of the current profile.
+func (c *config) autosave() {
+	path, err := profilePath(c.profile)
+	if err == nil {
+		err = c.writeSave(path)
+	}
+	if err != nil {
+		log.Println("Error: couldn't save:", err)
+	}
+}
+
+// resolveSave resolves the save file named by args, the one of the current profile if none is.
+func (c *config) resolveSave(args []string) (string, error) {
+	if len(args) == 0 {
+		return profilePath(c.profile)
+	}
+	return savePath(args[0])
+}
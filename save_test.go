@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JeanLeonHenry/pokedex/api"
+	"github.com/JeanLeonHenry/pokedex/pokecache"
+)
+
+// newTestConfig returns a config querying a fake PokeAPI, which knows every pokemon.
+func newTestConfig(t *testing.T) *config {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name, ok := strings.CutPrefix(r.URL.Path, "/pokemon/")
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, `{"name": %q, "base_experience": 112}`, name)
+	}))
+	t.Cleanup(server.Close)
+	client := api.NewClient(api.WithBaseURL(server.URL+"/"), api.WithRetry(api.RetryPolicy{}))
+	cache := pokecache.NewCache(time.Minute)
+	t.Cleanup(cache.Close)
+	return &config{client: client, cache: cache, trainer: newTrainer(), next: client.LocationAreaFirstPage(), previous: client.LocationAreaFirstPage()}
+}
+
+func TestSaveRoundTrip(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	path, err := savePath("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := newTestConfig(t)
+	cfg.next, cfg.previous = cfg.client.LocationAreaPage(40), cfg.client.LocationAreaPage(0)
+	cfg.trainer.Pokedex["pikachu"] = newCaughtPokemon(api.PokemonDetails{Name: "pikachu", BaseExperience: 112})
+	cfg.trainer.Inventory.Add("thunder-stone", 1)
+	if err := cfg.writeSave(path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// details are fetched again rather than saved
+	if data, _ := os.ReadFile(path); strings.Contains(string(data), "base_experience") {
+		t.Errorf("expected pokemon details to be left out, got %s", data)
+	}
+
+	// the save carries over to another api
+	loaded := newTestConfig(t)
+	if err := loaded.loadSave(path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	pikachu, ok, err := loaded.getCaught(context.Background(), "pikachu")
+	if !ok || err != nil || pikachu.Level() != cfg.trainer.Pokedex["pikachu"].Level() || pikachu.BaseExperience != 112 {
+		t.Errorf("expected to load pikachu, got %+v: %v", loaded.trainer.Pokedex, err)
+	}
+	if !loaded.trainer.Inventory.Has("thunder-stone") {
+		t.Errorf("expected to load inventory, got %v", loaded.trainer.Inventory)
+	}
+	if loaded.next != loaded.client.LocationAreaPage(40) || loaded.previous != loaded.client.LocationAreaPage(0) {
+		t.Errorf("expected to load map position, got %q and %q", loaded.next, loaded.previous)
+	}
+	// only the save itself is left behind
+	if files, _ := os.ReadDir(filepath.Dir(path)); len(files) != 1 {
+		t.Errorf("expected a single file, found %v", len(files))
+	}
+}
+
+func TestLoadSkipsEmptyPokemon(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "save.json")
+	data := `{"version": 2, "trainer": {"pokedex": {"pikachu": {"name": "pikachu"}, "ghost": null}}}`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := newTestConfig(t)
+	if err := cfg.loadSave(path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := cfg.trainer.Pokedex["ghost"]; ok || len(cfg.trainer.Pokedex) != 1 {
+		t.Errorf("expected only pikachu to be loaded, got %v", cfg.trainer.Pokedex)
+	}
+}
+
+func TestLoadMigratesVersion1(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "save.json")
+	data := `{"version": 1, "trainer": {"pokedex": {"pikachu": {"name": "pikachu", "base_experience": 1, "experience": 27}}},
+		"next": "https://pokeapi.co/api/v2/location-area/?offset=60&limit=20", "previous": ""}`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := newTestConfig(t)
+	if err := cfg.loadSave(path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pikachu, _, err := cfg.getCaught(context.Background(), "pikachu"); pikachu == nil || pikachu.Experience != 27 || pikachu.BaseExperience != 112 {
+		t.Errorf("expected pikachu with fresh details, got %+v: %v", pikachu, err)
+	}
+	if cfg.next != cfg.client.LocationAreaPage(60) || cfg.previous != "" {
+		t.Errorf("expected map position on this api, got %q and %q", cfg.next, cfg.previous)
+	}
+}
+
+func TestLoadRejectsNewerSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "save.json")
+	if err := os.WriteFile(path, []byte(`{"version": 999}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := newTestConfig(t)
+	if err := cfg.loadSave(path); !errors.Is(err, ErrNewerSave) {
+		t.Errorf("expected newer save to be rejected, got %v", err)
+	}
+}
+
+func TestLoadOffline(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "save.json")
+	data := `{"version": 2, "trainer": {"pokedex": {"pikachu": {"name": "pikachu", "experience": 27}}}}`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := newTestConfig(t)
+	cfg.client = api.NewClient(api.WithBaseURL("http://127.0.0.1:1/"), api.WithRetry(api.RetryPolicy{}))
+	if err := cfg.loadSave(path); err != nil {
+		t.Fatalf("expected save to load offline, got %v", err)
+	}
+	// only using the pokemon needs its details
+	if _, ok, err := cfg.getCaught(context.Background(), "pikachu"); !ok || err == nil {
+		t.Errorf("expected pikachu to be kept, but its details to be unavailable: %v", err)
+	}
+	if pikachu := cfg.trainer.Pokedex["pikachu"]; pikachu.Experience != 27 {
+		t.Errorf("expected pikachu to keep its experience, got %+v", pikachu)
+	}
+}
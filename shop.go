@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/JeanLeonHenry/pokedex/api"
+)
+
+// shopStock lists the items on display at the shop. Any other item with a price can be bought too.
+var shopStock = []string{
+	"poke-ball", "great-ball", "ultra-ball",
+	"potion", "super-potion",
+	"fire-stone", "water-stone", "thunder-stone", "leaf-stone", "moon-stone",
+}
+
+// sellPrice is what the shop pays for an item, half of its cost as in the games.
+func sellPrice(item api.Item) int {
+	return item.Cost / 2
+}
+
+func (c *config) shop(ctx context.Context, args ...string) {
+	usage := "usage: shop list | shop buy <item> [qty] | shop sell <item>"
+	switch {
+	case len(args) == 1 && args[0] == "list":
+		c.listShop(ctx)
+	case (len(args) == 2 || len(args) == 3) && args[0] == "buy":
+		qty := 1
+		if len(args) == 3 {
+			n, err := strconv.Atoi(args[2])
+			if err != nil || n < 1 {
+				log.Println(usage)
+				return
+			}
+			qty = n
+		}
+		c.buy(ctx, args[1], qty)
+	case len(args) == 2 && args[0] == "sell":
+		c.sell(ctx, args[1])
+	default:
+		log.Println(usage)
+	}
+}
+
+func (c *config) listShop(ctx context.Context) {
+	urls := make([]string, len(shopStock))
+	for i, name := range shopStock {
+		urls[i] = c.client.ItemURL(name)
+	}
+	items, err := getResources[api.Item](ctx, c, urls, c.client.GetItem)
+	if err != nil {
+		printError(err)
+		return
+	}
+	fmt.Println("Money:", formatMoney(c.trainer.Money))
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Item\tPrice\tOwned")
+	for _, item := range items {
+		fmt.Fprintf(w, "%v\t%v\t%v\n", item.Name, formatMoney(item.Cost), c.trainer.Inventory[item.Name])
+	}
+	w.Flush()
+}
+
+func (c *config) buy(ctx context.Context, itemName string, qty int) {
+	item, err := c.getItem(ctx, itemName)
+	if errors.Is(err, api.ErrNotFound) {
+		fmt.Println("No such item:", itemName)
+		return
+	} else if err != nil {
+		printError(err)
+		return
+	}
+	if item.Cost == 0 {
+		fmt.Println(itemName, "isn't for sale.")
+		return
+	}
+	// checked before multiplying, so that a huge qty can't overflow the total
+	if affordable := c.trainer.Money / item.Cost; qty > affordable {
+		fmt.Println("Not enough money:", item.Name, "costs", formatMoney(item.Cost), "and you can afford", affordable)
+		return
+	}
+	total := item.Cost * qty
+	c.trainer.Pay(total)
+	// itemName may be an id, the inventory is keyed by name
+	c.trainer.Inventory.Add(item.Name, qty)
+	fmt.Println("Bought", qty, item.Name, "for", formatMoney(total))
+}
+
+func (c *config) sell(ctx context.Context, itemName string) {
+	if !c.trainer.Inventory.Has(itemName) {
+		fmt.Println("No", itemName, "in inventory.")
+		return
+	}
+	item, err := c.getItem(ctx, itemName)
+	if err != nil {
+		printError(err)
+		return
+	}
+	price := sellPrice(item)
+	if price == 0 {
+		fmt.Println("The shop doesn't buy", itemName)
+		return
+	}
+	c.trainer.Inventory.Use(itemName)
+	c.trainer.Earn(price)
+	fmt.Println("Sold", itemName, "for", formatMoney(price))
+}
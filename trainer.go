@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/JeanLeonHenry/pokedex/api"
+)
+
+// Trainer is the state of the player: the pokemon they caught, the items they carry and their money.
+type Trainer struct {
+	Pokedex   Pokedex   `json:"pokedex"`
+	Inventory Inventory `json:"inventory"`
+	Money     int       `json:"money"`
+	StartedAt time.Time `json:"started_at"`
+}
+
+// startingMoney is what a new trainer has in their wallet.
+const startingMoney = 500
+
+func newTrainer() *Trainer {
+	return &Trainer{Pokedex: make(Pokedex), Inventory: newInventory(), Money: startingMoney, StartedAt: time.Now()}
+}
+
+// catchReward is the money earned by catching the given pokemon, scaled by the experience it's worth.
+func catchReward(details api.PokemonDetails) int {
+	return max(details.BaseExperience*2, 1)
+}
+
+// Pay takes amount from the wallet, and reports whether there was enough money.
+func (t *Trainer) Pay(amount int) bool {
+	if amount > t.Money {
+		return false
+	}
+	t.Money -= amount
+	return true
+}
+
+// Earn adds amount to the wallet.
+func (t *Trainer) Earn(amount int) {
+	t.Money += amount
+}
+
+// formatMoney formats an amount of money.
+func formatMoney(amount int) string {
+	return fmt.Sprintf("₽%v", amount)
+}
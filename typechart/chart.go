@@ -0,0 +1,67 @@
+// Package typechart computes how effective attacking types are against defending types.
+package typechart
+
+import (
+	"github.com/JeanLeonHenry/pokedex/api"
+)
+
+// Chart holds the damage multipliers between the types it was built from, by attacking then defending type.
+// Pairs it knows nothing about are neutral.
+type Chart map[string]map[string]float64
+
+// NewChart builds a chart from the damage relations of the given types in a generation, or the current
+// ones if generation is empty. Each type teaches the chart how it attacks and how it defends.
+func NewChart(generation string, types ...api.TypeDetails) Chart {
+	c := make(Chart)
+	for _, t := range types {
+		relations := t.DamageRelationsIn(generation)
+		for multiplier, attackers := range map[float64][]api.NamedResource{
+			0:   relations.NoDamageFrom,
+			0.5: relations.HalfDamageFrom,
+			2:   relations.DoubleDamageFrom,
+		} {
+			for _, attacker := range attackers {
+				c.set(attacker.Name, t.Name, multiplier)
+			}
+		}
+		for multiplier, defenders := range map[float64][]api.NamedResource{
+			0:   relations.NoDamageTo,
+			0.5: relations.HalfDamageTo,
+			2:   relations.DoubleDamageTo,
+		} {
+			for _, defender := range defenders {
+				c.set(t.Name, defender.Name, multiplier)
+			}
+		}
+	}
+	return c
+}
+
+func (c Chart) set(attacking, defending string, multiplier float64) {
+	if c[attacking] == nil {
+		c[attacking] = make(map[string]float64)
+	}
+	c[attacking][defending] = multiplier
+}
+
+// Multiplier returns the damage multiplier of an attacking type against the given defending types.
+func (c Chart) Multiplier(attacking string, defending ...string) float64 {
+	multiplier := 1.
+	for _, d := range defending {
+		if m, ok := c[attacking][d]; ok {
+			multiplier *= m
+		}
+	}
+	return multiplier
+}
+
+// Defense returns the multiplier of every attacking type that isn't neutral against the given defending types.
+func (c Chart) Defense(defending ...string) map[string]float64 {
+	result := make(map[string]float64)
+	for attacking := range c {
+		if multiplier := c.Multiplier(attacking, defending...); multiplier != 1 {
+			result[attacking] = multiplier
+		}
+	}
+	return result
+}
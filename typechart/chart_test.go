@@ -0,0 +1,66 @@
+package typechart
+
+import (
+	"testing"
+
+	"github.com/JeanLeonHenry/pokedex/api"
+)
+
+func named(names ...string) (resources []api.NamedResource) {
+	for _, name := range names {
+		resources = append(resources, api.NamedResource{Name: name})
+	}
+	return resources
+}
+
+var (
+	grass = api.TypeDetails{Name: "grass", DamageRelations: api.DamageRelations{
+		DoubleDamageFrom: named("fire", "ice", "poison", "flying", "bug"),
+		HalfDamageFrom:   named("ground", "water", "grass", "electric"),
+	}}
+	poison = api.TypeDetails{Name: "poison", DamageRelations: api.DamageRelations{
+		DoubleDamageFrom: named("ground", "psychic"),
+		HalfDamageFrom:   named("fighting", "poison", "bug", "grass", "fairy"),
+		NoDamageTo:       named("steel"),
+	}}
+	ghost = api.TypeDetails{
+		Name:            "ghost",
+		DamageRelations: api.DamageRelations{NoDamageFrom: named("normal", "fighting"), DoubleDamageFrom: named("ghost", "dark")},
+		PastDamageRelations: []api.PastDamageRelations{{
+			Generation:      api.NamedResource{Name: "generation-i"},
+			DamageRelations: api.DamageRelations{NoDamageFrom: named("normal", "fighting"), DoubleDamageFrom: named("ghost")},
+		}},
+	}
+)
+
+func TestMultiplier(t *testing.T) {
+	chart := NewChart("", grass, poison, ghost)
+	cases := []struct {
+		attacking string
+		defending []string
+		want      float64
+	}{
+		{attacking: "fire", defending: []string{"grass", "poison"}, want: 2},
+		{attacking: "bug", defending: []string{"grass", "poison"}, want: 1},
+		{attacking: "grass", defending: []string{"grass", "poison"}, want: 0.25},
+		{attacking: "normal", defending: []string{"ghost"}, want: 0},
+		{attacking: "poison", defending: []string{"steel"}, want: 0},
+		{attacking: "dragon", defending: []string{"grass"}, want: 1},
+	}
+	for _, c := range cases {
+		t.Run(c.attacking, func(t *testing.T) {
+			if got := chart.Multiplier(c.attacking, c.defending...); got != c.want {
+				t.Errorf("expected %vx, got %vx", c.want, got)
+			}
+		})
+	}
+}
+
+func TestPastGeneration(t *testing.T) {
+	if got := NewChart("generation-i", ghost).Multiplier("dark", "ghost"); got != 1 {
+		t.Errorf("expected dark to be neutral in generation i, got %vx", got)
+	}
+	if got := NewChart("generation-ii", ghost).Multiplier("dark", "ghost"); got != 2 {
+		t.Errorf("expected dark to be super effective from generation ii, got %vx", got)
+	}
+}
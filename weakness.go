@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/JeanLeonHenry/pokedex/api"
+	"github.com/JeanLeonHenry/pokedex/typechart"
+)
+
+// getTypeChart builds the chart of the given types in a generation, or the current one if generation is empty.
+func (c *config) getTypeChart(ctx context.Context, generation string, typeNames ...string) (typechart.Chart, error) {
+	types := make([]api.TypeDetails, len(typeNames))
+	for i, name := range typeNames {
+		err := getResource[api.TypeDetails](ctx, c, c.client.TypeURL(name), &types[i], c.client.GetTypeDetails)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return typechart.NewChart(generation, types...), nil
+}
+
+func (c *config) printWeaknesses(ctx context.Context, args ...string) {
+	positional, options, err := parseArgs(args, "generation")
+	if err != nil || len(positional) != 1 {
+		log.Println("usage: weakness <pokemon> [--generation <generation>]")
+		return
+	}
+	pokemonName := positional[0]
+	generation := options["generation"]
+	if generation != "" && api.GenerationNumber(generation) == 0 {
+		fmt.Println("No such generation:", generation, "(try generation-iv)")
+		return
+	}
+	details, err := c.getPokemon(ctx, pokemonName)
+	if errors.Is(err, api.ErrNotFound) {
+		fmt.Println("No such pokemon:", pokemonName)
+		return
+	} else if err != nil {
+		printError(err)
+		return
+	}
+	typeNames := details.TypesIn(generation)
+	chart, err := c.getTypeChart(ctx, generation, typeNames...)
+	if err != nil {
+		printError(err)
+		return
+	}
+
+	byMultiplier := make(map[float64][]string)
+	for attacking, multiplier := range chart.Defense(typeNames...) {
+		byMultiplier[multiplier] = append(byMultiplier[multiplier], attacking)
+	}
+	fmt.Printf("%v (%v):\n", pokemonName, strings.Join(typeNames, "/"))
+	for _, multiplier := range []float64{4, 2, 0.5, 0.25, 0} {
+		attacking := byMultiplier[multiplier]
+		if len(attacking) == 0 {
+			continue
+		}
+		slices.Sort(attacking)
+		fmt.Printf("\t%vx: %v\n", multiplier, strings.Join(attacking, ", "))
+	}
+}